@@ -0,0 +1,212 @@
+package ta
+
+import (
+	"time"
+
+	"go.oneofone.dev/ta/decimal"
+)
+
+// Trade is a single timestamped trade (or quote), the input of BarBuilder
+type Trade struct {
+	Time  time.Time
+	Price Decimal
+	Size  int
+}
+
+// Bar is a candle and the start time of its interval
+type Bar struct {
+	Candle
+	Start time.Time
+}
+
+// Bars is a slice of bars, the output of a BarAggregator
+type Bars []*Bar
+
+// Candles returns the bars as candles
+func (bs Bars) Candles() []*Candle {
+	out := make([]*Candle, 0, len(bs))
+	for _, b := range bs {
+		out = append(out, &b.Candle)
+	}
+	return out
+}
+
+// Close returns only the close values as a TA, to be used with ApplyStudy
+func (bs Bars) Close() *TA {
+	out := NewSize(len(bs), true)
+	for _, b := range bs {
+		out.Append(b.Close)
+	}
+	return out
+}
+
+// Columns returns the bars as candle columns, to be used with ApplyCandles
+func (bs Bars) Columns() *Candles {
+	cs := &Candles{
+		Open:   NewSize(len(bs), true),
+		High:   NewSize(len(bs), true),
+		Low:    NewSize(len(bs), true),
+		Close:  NewSize(len(bs), true),
+		Volume: NewSize(len(bs), true),
+	}
+	for _, b := range bs {
+		cs.Open.Append(b.Open)
+		cs.High.Append(b.High)
+		cs.Low.Append(b.Low)
+		cs.Close.Append(b.Close)
+		cs.Volume.Append(Decimal(b.Volume))
+	}
+	return cs
+}
+
+// BarAggregator builds bars from trades, see NewBarBuilder, TickBars, VolumeBars, DollarBars and the imbalance bars
+type BarAggregator interface {
+	// Update adds a trade and returns the bars closed by it, if any
+	Update(t Trade) Bars
+	// Flush closes and returns the forming bar, if any
+	Flush() Bars
+}
+
+// ApplyBars feeds the trades to a and returns all the bars, including the forming bar
+func ApplyBars(a BarAggregator, trades []Trade) Bars {
+	var out Bars
+	for _, t := range trades {
+		out = append(out, a.Update(t)...)
+	}
+	return append(out, a.Flush()...)
+}
+
+var _ BarAggregator = (*BarBuilder)(nil)
+
+// BarBuilder aggregates trades or smaller candles into OHLCV bars of a fixed interval,
+// it only uses the timestamps it's given, never the wall clock, so backtests and live data produce the same bars.
+type BarBuilder struct {
+	session  SessionFunc
+	interval time.Duration
+
+	cur   *Bar      // the forming bar
+	end   time.Time // the end of cur or of the last closed bar
+	sess  time.Time // the session of cur or of the last closed bar
+	close Decimal   // the close of the last closed bar
+	fill  bool
+	set   bool
+}
+
+// NewBarBuilder returns a BarBuilder for the given interval, for example time.Minute or 24*time.Hour.
+// Bars are aligned to the start of their session and never cross a session, if session is nil,
+// bars are aligned to multiples of interval since the zero time, which means UTC boundaries for intervals up to a day.
+// If fill is true, intervals without trades in the same session return a flat bar at the last close with no volume,
+// otherwise they are skipped.
+func NewBarBuilder(interval time.Duration, session SessionFunc, fill bool) *BarBuilder {
+	if interval <= 0 {
+		panic("bars: interval must be > 0")
+	}
+	return &BarBuilder{session: session, interval: interval, fill: fill}
+}
+
+// Update adds a trade and returns the bars closed by it, if any,
+// trades older than the forming bar are ignored.
+func (b *BarBuilder) Update(t Trade) Bars {
+	return b.add(t.Time, &Candle{Open: t.Price, High: t.Price, Low: t.Price, Close: t.Price, Volume: t.Size})
+}
+
+// UpdateCandle same as Update for a candle that starts at ts, used to resample to a higher interval
+func (b *BarBuilder) UpdateCandle(ts time.Time, c *Candle) Bars {
+	return b.add(ts, c)
+}
+
+// Advance closes the forming bar, and fills any empty intervals, if ts is past its end,
+// it allows closing bars on a timer in live trading without waiting for the next trade.
+func (b *BarBuilder) Advance(ts time.Time) Bars {
+	if !b.set || ts.Before(b.end) {
+		return nil
+	}
+	start, sess := b.align(ts)
+	return b.closeUntil(start, sess)
+}
+
+// Current returns a copy of the forming bar or nil if there isn't one
+func (b *BarBuilder) Current() *Bar {
+	if b.cur == nil {
+		return nil
+	}
+	cp := *b.cur
+	return &cp
+}
+
+// Flush closes and returns the forming bar, if any, it should be called at the end of the data
+func (b *BarBuilder) Flush() Bars {
+	if b.cur == nil {
+		return nil
+	}
+	return b.closeUntil(b.end, b.sess)
+}
+
+func (b *BarBuilder) add(ts time.Time, c *Candle) Bars {
+	if cur := b.cur; cur != nil {
+		if ts.Before(cur.Start) {
+			return nil
+		}
+		if ts.Before(b.end) && (b.session == nil || b.session(ts).Equal(b.sess)) {
+			cur.High = decimal.Max(cur.High, c.High)
+			cur.Low = decimal.Min(cur.Low, c.Low)
+			cur.Close = c.Close
+			cur.Volume += c.Volume
+			return nil
+		}
+	} else if b.set && ts.Before(b.end) {
+		// already closed by Advance
+		return nil
+	}
+
+	start, sess := b.align(ts)
+	out := b.closeUntil(start, sess)
+	b.cur = &Bar{Start: start, Candle: *c}
+	b.end, b.sess, b.set = start.Add(b.interval), sess, true
+	return out
+}
+
+// closeUntil closes the forming bar and fills the empty intervals before start
+func (b *BarBuilder) closeUntil(start, sess time.Time) (out Bars) {
+	if cur := b.cur; cur != nil {
+		out = append(out, cur)
+		b.close, b.cur = cur.Close, nil
+	}
+
+	if !b.set || !b.fill || !sess.Equal(b.sess) {
+		return
+	}
+
+	for ; b.end.Before(start); b.end = b.end.Add(b.interval) {
+		c := b.close
+		out = append(out, &Bar{Start: b.end, Candle: Candle{Open: c, High: c, Low: c, Close: c}})
+	}
+	return
+}
+
+// align returns the start of the bar ts belongs to and its session
+func (b *BarBuilder) align(ts time.Time) (start, sess time.Time) {
+	if b.session == nil {
+		return ts.Truncate(b.interval), time.Time{}
+	}
+	sess = b.session(ts)
+	return sess.Add(ts.Sub(sess) / b.interval * b.interval), sess
+}
+
+// BarPipe aggregates the trades from in using a and sends the closed bars to the returned channel,
+// the forming bar is flushed and the channel is closed once in is closed.
+func BarPipe(a BarAggregator, in <-chan Trade) <-chan *Bar {
+	ch := make(chan *Bar, 100)
+	go func() {
+		for t := range in {
+			for _, bar := range a.Update(t) {
+				ch <- bar
+			}
+		}
+		for _, bar := range a.Flush() {
+			ch <- bar
+		}
+		close(ch)
+	}()
+	return ch
+}
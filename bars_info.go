@@ -0,0 +1,148 @@
+package ta
+
+import "go.oneofone.dev/ta/decimal"
+
+// TickBars returns a BarAggregator that closes a bar every n trades
+func TickBars(n int) BarAggregator {
+	checkPeriod(n, 1)
+	return &thresholdBars{n: Decimal(n), value: func(Trade) Decimal { return 1 }}
+}
+
+// VolumeBars returns a BarAggregator that closes a bar once it has at least n shares,
+// trades aren't split, so a bar can have more than n.
+func VolumeBars(n int) BarAggregator {
+	checkPeriod(n, 1)
+	return &thresholdBars{n: Decimal(n), value: func(t Trade) Decimal { return Decimal(t.Size) }}
+}
+
+// DollarBars returns a BarAggregator that closes a bar once it has traded at least n notional (price * size),
+// trades aren't split, so a bar can have more than n.
+func DollarBars(n Decimal) BarAggregator {
+	if n <= 0 {
+		panic("bars: n must be > 0")
+	}
+	return &thresholdBars{n: n, value: func(t Trade) Decimal { return t.Price * Decimal(t.Size) }}
+}
+
+var _ BarAggregator = (*thresholdBars)(nil)
+
+type thresholdBars struct {
+	value func(t Trade) Decimal
+
+	cur    *Bar
+	n, acc Decimal
+}
+
+func (a *thresholdBars) Update(t Trade) Bars {
+	a.cur = addTrade(a.cur, t)
+	if a.acc += a.value(t); a.acc < a.n {
+		return nil
+	}
+	a.acc = 0
+	return a.Flush()
+}
+
+func (a *thresholdBars) Flush() Bars {
+	return flushBar(&a.cur)
+}
+
+// TickImbalanceBars returns a BarAggregator that closes a bar once the imbalance of buys and sells,
+// signed by the tick rule, exceeds its expected value, the expected number of trades per bar and the
+// expected imbalance per trade are EWMAs of the previous bars with alpha = 2 / (window + 1).
+// The first bar closes after expTicks trades and is used to initialize the expected values.
+func TickImbalanceBars(expTicks, window int) BarAggregator {
+	return newImbalanceBars(expTicks, window, false)
+}
+
+// VolumeImbalanceBars same as TickImbalanceBars, except each trade is weighted by its size
+func VolumeImbalanceBars(expTicks, window int) BarAggregator {
+	return newImbalanceBars(expTicks, window, true)
+}
+
+func newImbalanceBars(expTicks, window int, volume bool) *imbalanceBars {
+	checkPeriod(expTicks, 1)
+	checkPeriod(window, 1)
+	return &imbalanceBars{
+		alpha:  2 / Decimal(window+1),
+		expT:   Decimal(expTicks),
+		volume: volume,
+	}
+}
+
+var _ BarAggregator = (*imbalanceBars)(nil)
+
+type imbalanceBars struct {
+	cur *Bar
+
+	alpha      Decimal
+	prev, sign Decimal // the tick rule, the sign of the last price change
+
+	theta Decimal // the imbalance of the forming bar
+	ticks int
+
+	expT, expB Decimal // the expected ticks per bar and imbalance per tick
+
+	volume bool
+	seen   bool // prev is set
+	set    bool // the expected values are known
+}
+
+func (a *imbalanceBars) Update(t Trade) Bars {
+	switch {
+	case !a.seen:
+	case t.Price > a.prev:
+		a.sign = 1
+	case t.Price < a.prev:
+		a.sign = -1
+	}
+	a.prev, a.seen = t.Price, true
+	a.cur = addTrade(a.cur, t)
+
+	b := a.sign
+	if a.volume {
+		b *= Decimal(t.Size)
+	}
+	a.theta += b
+	a.ticks++
+
+	ticks := Decimal(a.ticks)
+	if !a.set {
+		if ticks < a.expT {
+			return nil
+		}
+		a.expT, a.expB, a.set = ticks, a.theta/ticks, true
+	} else {
+		if a.theta.Abs() < a.expT*a.expB.Abs() {
+			return nil
+		}
+		a.expT = decimal.Max(1, a.expT+a.alpha*(ticks-a.expT))
+		a.expB += a.alpha * (a.theta/ticks - a.expB)
+	}
+
+	a.theta, a.ticks = 0, 0
+	return a.Flush()
+}
+
+func (a *imbalanceBars) Flush() Bars {
+	return flushBar(&a.cur)
+}
+
+func addTrade(b *Bar, t Trade) *Bar {
+	if b == nil {
+		return &Bar{Start: t.Time, Candle: Candle{Open: t.Price, High: t.Price, Low: t.Price, Close: t.Price, Volume: t.Size}}
+	}
+	b.High = decimal.Max(b.High, t.Price)
+	b.Low = decimal.Min(b.Low, t.Price)
+	b.Close = t.Price
+	b.Volume += t.Size
+	return b
+}
+
+func flushBar(b **Bar) Bars {
+	if *b == nil {
+		return nil
+	}
+	out := Bars{*b}
+	*b = nil
+	return out
+}
@@ -0,0 +1,204 @@
+package ta
+
+import (
+	"testing"
+	"time"
+)
+
+func checkTimedBars(t *testing.T, name string, got []*Bar, exp []Bar) {
+	t.Helper()
+	if len(got) != len(exp) {
+		t.Fatalf("%s: expected %d bars, got %d: %v", name, len(exp), len(got), got)
+	}
+	for i, b := range got {
+		if !b.Start.Equal(exp[i].Start) || b.Candle != exp[i].Candle {
+			t.Fatalf("%s [%d]: expected %v %+v, got %v %+v", name, i, exp[i].Start, exp[i].Candle, b.Start, b.Candle)
+		}
+	}
+}
+
+func TestBarBuilder(t *testing.T) {
+	t0 := time.Date(2021, 3, 1, 9, 30, 0, 0, time.UTC)
+	trades := []Trade{
+		{t0.Add(5 * time.Second), 10, 1},
+		{t0.Add(20 * time.Second), 12, 2},
+		{t0.Add(40 * time.Second), 9, 1},
+		{t0.Add(59 * time.Second), 11, 3},
+		{t0.Add(60 * time.Second), 11.5, 1},
+		// 9:32 and 9:33 are empty
+		{t0.Add(4*time.Minute + time.Second), 13, 2},
+		{t0.Add(4*time.Minute + 2*time.Second), 12.5, 2},
+	}
+	run := func(b *BarBuilder) (out []*Bar) {
+		for _, tr := range trades {
+			out = append(out, b.Update(tr)...)
+		}
+		return append(out, b.Flush()...)
+	}
+
+	checkTimedBars(t, "skip", run(NewBarBuilder(time.Minute, nil, false)), []Bar{
+		{Candle{10, 12, 9, 11, 7}, t0},
+		{Candle{11.5, 11.5, 11.5, 11.5, 1}, t0.Add(time.Minute)},
+		{Candle{13, 13, 12.5, 12.5, 4}, t0.Add(4 * time.Minute)},
+	})
+
+	checkTimedBars(t, "fill", run(NewBarBuilder(time.Minute, nil, true)), []Bar{
+		{Candle{10, 12, 9, 11, 7}, t0},
+		{Candle{11.5, 11.5, 11.5, 11.5, 1}, t0.Add(time.Minute)},
+		{Candle{11.5, 11.5, 11.5, 11.5, 0}, t0.Add(2 * time.Minute)},
+		{Candle{11.5, 11.5, 11.5, 11.5, 0}, t0.Add(3 * time.Minute)},
+		{Candle{13, 13, 12.5, 12.5, 4}, t0.Add(4 * time.Minute)},
+	})
+
+	checkTimedBars(t, "5m", run(NewBarBuilder(5*time.Minute, nil, false)), []Bar{
+		{Candle{10, 13, 9, 12.5, 12}, t0},
+	})
+
+	// resampling gives the same bars as the trades
+	var (
+		b1m = run(NewBarBuilder(time.Minute, nil, false))
+		b5m = NewBarBuilder(5*time.Minute, nil, false)
+		out []*Bar
+	)
+	for _, b := range b1m {
+		out = append(out, b5m.UpdateCandle(b.Start, &b.Candle)...)
+	}
+	checkTimedBars(t, "resample", append(out, b5m.Flush()...), []Bar{
+		{Candle{10, 13, 9, 12.5, 12}, t0},
+	})
+
+	// a bar is closed by event time, not by the wall clock
+	b := NewBarBuilder(time.Minute, nil, true)
+	b.Update(trades[0])
+	if out := b.Advance(t0.Add(59 * time.Second)); out != nil {
+		t.Fatalf("unexpected bars %v", out)
+	}
+	checkTimedBars(t, "advance", b.Advance(t0.Add(2*time.Minute+time.Second)), []Bar{
+		{Candle{10, 10, 10, 10, 1}, t0},
+		{Candle{10, 10, 10, 10, 0}, t0.Add(time.Minute)},
+	})
+	if out := b.Update(trades[1]); out != nil || b.Current() != nil {
+		t.Fatalf("late trades should be ignored, got %v %v", out, b.Current())
+	}
+	checkTimedBars(t, "after advance", append(b.Update(trades[5]), b.Flush()...), []Bar{
+		{Candle{10, 10, 10, 10, 0}, t0.Add(2 * time.Minute)},
+		{Candle{10, 10, 10, 10, 0}, t0.Add(3 * time.Minute)},
+		{Candle{13, 13, 13, 13, 2}, t0.Add(4 * time.Minute)},
+	})
+}
+
+func TestBarBuilderSessions(t *testing.T) {
+	// 1h bars aligned to a 9:30 open, nothing is filled between sessions
+	var (
+		d1   = time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC)
+		d2   = d1.AddDate(0, 0, 1)
+		open = 9*time.Hour + 30*time.Minute
+		b    = NewBarBuilder(time.Hour, DailySessions(time.UTC, open), true)
+		out  []*Bar
+	)
+	for _, tr := range []Trade{
+		{d1.Add(open), 10, 1},
+		{d1.Add(open + 59*time.Minute), 11, 1},
+		{d1.Add(open + 3*time.Hour), 12, 1},
+		{d2.Add(open + 2*time.Hour + 10*time.Minute), 13, 1},
+	} {
+		out = append(out, b.Update(tr)...)
+	}
+	checkTimedBars(t, "sessions", append(out, b.Flush()...), []Bar{
+		{Candle{10, 11, 10, 11, 2}, d1.Add(open)},
+		{Candle{11, 11, 11, 11, 0}, d1.Add(open + time.Hour)},
+		{Candle{11, 11, 11, 11, 0}, d1.Add(open + 2*time.Hour)},
+		{Candle{12, 12, 12, 12, 1}, d1.Add(open + 3*time.Hour)},
+		{Candle{13, 13, 13, 13, 1}, d2.Add(open + 2*time.Hour)},
+	})
+
+	// daily bars in a different location
+	nyc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip(err)
+	}
+	b = NewBarBuilder(24*time.Hour, DailySessions(nyc, 0), false)
+	b.Update(Trade{time.Date(2021, 3, 1, 23, 0, 0, 0, nyc), 10, 1})
+	out = b.Update(Trade{time.Date(2021, 3, 2, 1, 0, 0, 0, nyc), 11, 1})
+	checkTimedBars(t, "nyc", out, []Bar{{Candle{10, 10, 10, 10, 1}, time.Date(2021, 3, 1, 0, 0, 0, 0, nyc)}})
+}
+
+func TestBarPipe(t *testing.T) {
+	t0 := time.Unix(0, 0)
+	in := make(chan Trade)
+	go func() {
+		for i := 0; i < 10; i++ {
+			in <- Trade{t0.Add(time.Duration(i) * 30 * time.Second), Decimal(i), 1}
+		}
+		close(in)
+	}()
+
+	var got []*Bar
+	for b := range BarPipe(NewBarBuilder(time.Minute, nil, false), in) {
+		got = append(got, b)
+	}
+	if len(got) != 5 || got[4].Candle != (Candle{8, 9, 8, 9, 2}) {
+		t.Fatalf("unexpected bars %v", got)
+	}
+}
+
+func tradesAt(t0 time.Time, prices []Decimal, sizes ...int) []Trade {
+	out := make([]Trade, 0, len(prices))
+	for i, p := range prices {
+		size := 1
+		if i < len(sizes) {
+			size = sizes[i]
+		}
+		out = append(out, Trade{t0.Add(time.Duration(i) * time.Second), p, size})
+	}
+	return out
+}
+
+func TestInformationBars(t *testing.T) {
+	t0 := time.Date(2021, 3, 1, 9, 30, 0, 0, time.UTC)
+	trades := tradesAt(t0, []Decimal{10, 11, 12, 13, 14, 13, 12}, 1, 2, 1, 3, 1, 1, 5)
+
+	checkTimedBars(t, "tick", ApplyBars(TickBars(3), trades), []Bar{
+		{Candle{10, 12, 10, 12, 4}, t0},
+		{Candle{13, 14, 13, 13, 5}, t0.Add(3 * time.Second)},
+		{Candle{12, 12, 12, 12, 5}, t0.Add(6 * time.Second)},
+	})
+
+	checkTimedBars(t, "volume", ApplyBars(VolumeBars(3), trades), []Bar{
+		{Candle{10, 11, 10, 11, 3}, t0},
+		{Candle{12, 13, 12, 13, 4}, t0.Add(2 * time.Second)},
+		{Candle{14, 14, 12, 12, 7}, t0.Add(4 * time.Second)},
+	})
+
+	checkTimedBars(t, "dollar", ApplyBars(DollarBars(40), trades), []Bar{
+		{Candle{10, 12, 10, 12, 4}, t0},
+		{Candle{13, 14, 13, 14, 4}, t0.Add(3 * time.Second)},
+		{Candle{13, 13, 12, 12, 6}, t0.Add(5 * time.Second)},
+	})
+
+	// the first bar sets the expected values: 4 ticks, 0.75 imbalance per tick,
+	// the second bar needs |3| and updates them to 4.5 and 0.075, so the third bar needs 0.3375
+	trades = tradesAt(t0, []Decimal{10, 11, 12, 13, 14, 13, 12, 11, 10, 10})
+	imb := ApplyBars(TickImbalanceBars(4, 3), trades)
+	checkTimedBars(t, "tick imbalance", imb, []Bar{
+		{Candle{10, 13, 10, 13, 4}, t0},
+		{Candle{14, 14, 10, 10, 5}, t0.Add(4 * time.Second)},
+		{Candle{10, 10, 10, 10, 1}, t0.Add(9 * time.Second)},
+	})
+
+	// the same with sizes, the second bar needs |12| (4 * 3) and the third one |6.75| (3 * 2.25)
+	trades = tradesAt(t0, []Decimal{10, 11, 12, 13, 12, 11, 10, 9}, 1, 4, 4, 4, 10, 5, 5, 1)
+	checkTimedBars(t, "volume imbalance", ApplyBars(VolumeImbalanceBars(4, 3), trades), []Bar{
+		{Candle{10, 13, 10, 13, 13}, t0},
+		{Candle{12, 12, 11, 11, 15}, t0.Add(4 * time.Second)},
+		{Candle{10, 10, 9, 9, 6}, t0.Add(6 * time.Second)},
+	})
+
+	// the bars can be used with any study
+	if sma := ApplyStudy(SMA(2), imb.Close()); sma.Len() != 2 || sma.Get(-1) != 10 {
+		t.Fatalf("unexpected sma %v", sma)
+	}
+	if atr := ApplyCandles(ATR(2), imb.Columns())[0]; atr.Len() != 2 {
+		t.Fatalf("unexpected atr %v", atr)
+	}
+}
@@ -7,3 +7,8 @@ func BenchmarkEMA(b *testing.B)  { benchMA(b, "EMA", 10, EMA) }
 func BenchmarkWMA(b *testing.B)  { benchMA(b, "WMA", 10, WMA) }
 func BenchmarkDEMA(b *testing.B) { benchMA(b, "DEMA", 10, DEMA) }
 func BenchmarkTEMA(b *testing.B) { benchMA(b, "TEMA", 10, TEMA) }
+
+func BenchmarkTRIMA(b *testing.B) { benchMA(b, "TRIMA", 10, TRIMA) }
+func BenchmarkKAMA(b *testing.B)  { benchMA(b, "KAMA", 10, KAMA) }
+func BenchmarkT3(b *testing.B)    { benchMA(b, "T3", 10, T3) }
+func BenchmarkMAMA(b *testing.B)  { benchMA(b, "MAMA", 10, MAMA) }
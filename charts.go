@@ -0,0 +1,289 @@
+package ta
+
+import (
+	"math"
+
+	"go.oneofone.dev/ta/decimal"
+)
+
+// CandleTransform turns candles into a different type of bars, for example Heikin-Ashi candles or Renko bricks,
+// the output can be fed to any CandleStudy.
+type CandleTransform interface {
+	// Update returns the bars completed by c, if any
+	Update(c *Candle) []*Candle
+}
+
+// ApplyTransform applies the transform to the candles and returns all the completed bars
+func ApplyTransform(t CandleTransform, candles []*Candle) []*Candle {
+	out := make([]*Candle, 0, len(candles))
+	for _, c := range candles {
+		out = append(out, t.Update(c)...)
+	}
+	return out
+}
+
+// HeikinAshi returns a transform that returns a Heikin-Ashi candle for every candle
+func HeikinAshi() CandleTransform {
+	return &heikinAshi{}
+}
+
+type heikinAshi struct {
+	prev *Candle
+}
+
+func (t *heikinAshi) Update(c *Candle) []*Candle {
+	ha := &Candle{
+		Close:  (c.Open + c.High + c.Low + c.Close) / 4,
+		Volume: c.Volume,
+	}
+	if t.prev == nil {
+		ha.Open = (c.Open + c.Close) / 2
+	} else {
+		ha.Open = (t.prev.Open + t.prev.Close) / 2
+	}
+	ha.High = decimal.Max(c.High, ha.Open, ha.Close)
+	ha.Low = decimal.Min(c.Low, ha.Open, ha.Close)
+	t.prev = ha
+	return []*Candle{ha}
+}
+
+// Renko returns a transform that returns a brick every time the close moves box past the last brick,
+// so a reversal needs 2 boxes, the open and close of a brick are its bounds
+// and the volume since the last brick goes to the first returned brick.
+func Renko(box Decimal) CandleTransform {
+	if box <= 0 {
+		panic("renko: box must be > 0")
+	}
+	return &renko{box: box}
+}
+
+// RenkoATR same as Renko, except the box is ATR(period) of the first period+1 candles, no bricks are returned before that
+func RenkoATR(period int) CandleTransform {
+	return &renko{atr: ATR(period), period: period}
+}
+
+type renko struct {
+	atr Study
+
+	box         Decimal
+	top, bottom Decimal // the bounds of the last brick, both are the first close until the first brick
+	vol         int
+	period      int
+	count       int
+	set         bool
+}
+
+func (t *renko) Update(c *Candle) []*Candle {
+	if t.box == 0 {
+		v := t.atr.Update(c.High, c.Low, c.Close)
+		if t.count++; t.count <= t.period {
+			return nil
+		}
+		t.box = v
+	}
+
+	t.vol += c.Volume
+	if !t.set {
+		t.top, t.bottom, t.set = c.Close, c.Close, true
+		return nil
+	}
+
+	var out []*Candle
+	for {
+		var b Candle
+		switch {
+		case c.Close >= t.top+t.box:
+			b.Open, b.Close = t.top, t.top+t.box
+		case c.Close <= t.bottom-t.box:
+			b.Open, b.Close = t.bottom, t.bottom-t.box
+		default:
+			return out
+		}
+		t.top, t.bottom = decimal.Max(b.Open, b.Close), decimal.Min(b.Open, b.Close)
+		b.High, b.Low = t.top, t.bottom
+		b.Volume, t.vol = t.vol, 0
+		out = append(out, &b)
+	}
+}
+
+// Kagi returns a transform that returns a line every time the close reverses by reversal from the line's extreme,
+// if pct is true, reversal is a percent of the extreme, the open of a line is where it started and the close is its extreme.
+func Kagi(reversal Decimal, pct bool) CandleTransform {
+	if reversal <= 0 {
+		panic("kagi: reversal must be > 0")
+	}
+	if pct {
+		reversal /= 100
+	}
+	return &kagi{rev: reversal, pct: pct}
+}
+
+type kagi struct {
+	rev        Decimal
+	start, ext Decimal
+	dir        int
+	vol        int
+	pct        bool
+	set        bool
+}
+
+func (t *kagi) Update(c *Candle) []*Candle {
+	p := c.Close
+	t.vol += c.Volume
+	if !t.set {
+		t.start, t.ext, t.set = p, p, true
+		return nil
+	}
+
+	rev := t.rev
+	if t.pct {
+		rev *= t.ext.Abs()
+	}
+
+	switch {
+	case t.dir == 0:
+		if p != t.start {
+			t.ext, t.dir = p, 1
+			if p < t.start {
+				t.dir = -1
+			}
+		}
+	case t.dir > 0 && p > t.ext, t.dir < 0 && p < t.ext:
+		t.ext = p
+	case t.dir > 0 && t.ext-p >= rev, t.dir < 0 && p-t.ext >= rev:
+		line := &Candle{
+			Open:   t.start,
+			High:   decimal.Max(t.start, t.ext),
+			Low:    decimal.Min(t.start, t.ext),
+			Close:  t.ext,
+			Volume: t.vol,
+		}
+		t.start, t.ext, t.dir, t.vol = t.ext, p, -t.dir, 0
+		return []*Candle{line}
+	}
+	return nil
+}
+
+// PointAndFigure returns a transform that returns a column every time the close reverses by reversal boxes,
+// X columns open at their bottom and close at their top, O columns are the other way around.
+func PointAndFigure(box Decimal, reversal int) CandleTransform {
+	if box <= 0 || reversal < 1 {
+		panic("p&f: box must be > 0 and reversal >= 1")
+	}
+	return &pnf{box: box, rev: Decimal(reversal) * box}
+}
+
+type pnf struct {
+	box, rev    Decimal
+	top, bottom Decimal
+	ref         Decimal
+	dir         int
+	vol         int
+	set         bool
+}
+
+func (t *pnf) floor(v Decimal) Decimal { return Decimal(math.Floor(float64(v/t.box)+1e-9)) * t.box }
+func (t *pnf) ceil(v Decimal) Decimal  { return Decimal(math.Ceil(float64(v/t.box)-1e-9)) * t.box }
+
+func (t *pnf) Update(c *Candle) []*Candle {
+	p := c.Close
+	t.vol += c.Volume
+	if !t.set {
+		t.ref, t.set = t.floor(p), true
+		return nil
+	}
+
+	var col *Candle
+	switch t.dir {
+	case 0:
+		if up := t.floor(p); up >= t.ref+t.box {
+			t.bottom, t.top, t.dir = t.ref, up, 1
+		} else if down := t.ceil(p); down <= t.ref-t.box {
+			t.top, t.bottom, t.dir = t.ref, down, -1
+		}
+	case 1:
+		if up := t.floor(p); up > t.top {
+			t.top = up
+		} else if down := t.ceil(p); down <= t.top-t.rev {
+			col = &Candle{Open: t.bottom, Close: t.top}
+			t.top, t.bottom, t.dir = t.top-t.box, down, -1
+		}
+	case -1:
+		if down := t.ceil(p); down < t.bottom {
+			t.bottom = down
+		} else if up := t.floor(p); up >= t.bottom+t.rev {
+			col = &Candle{Open: t.top, Close: t.bottom}
+			t.bottom, t.top, t.dir = t.bottom+t.box, up, 1
+		}
+	}
+
+	if col == nil {
+		return nil
+	}
+	col.High, col.Low = decimal.Max(col.Open, col.Close), decimal.Min(col.Open, col.Close)
+	col.Volume, t.vol = t.vol, 0
+	return []*Candle{col}
+}
+
+// LineBreak returns an N-line break transform, a line is added when the close goes past the last line,
+// a reversal needs the close to break the extreme of the last n lines and starts at the open of the last line.
+func LineBreak(n int) CandleTransform {
+	checkPeriod(n, 1)
+	return &lineBreak{n: n}
+}
+
+type lineBreak struct {
+	lines []Candle // the last n lines
+	base  Decimal
+	vol   int
+	n     int
+	set   bool
+}
+
+func (t *lineBreak) Update(c *Candle) []*Candle {
+	p := c.Close
+	t.vol += c.Volume
+	if !t.set {
+		t.base, t.set = p, true
+		return nil
+	}
+
+	var open Decimal
+	if len(t.lines) == 0 {
+		if p == t.base {
+			return nil
+		}
+		open = t.base
+	} else {
+		var (
+			last   = &t.lines[len(t.lines)-1]
+			up     = last.Close > last.Open
+			hi, lo = last.High, last.Low
+		)
+		for i := range t.lines {
+			hi, lo = decimal.Max(hi, t.lines[i].High), decimal.Min(lo, t.lines[i].Low)
+		}
+
+		switch {
+		case up && p > last.Close, !up && p < last.Close:
+			open = last.Close
+		case up && p < lo, !up && p > hi:
+			open = last.Open
+		default:
+			return nil
+		}
+	}
+
+	line := Candle{
+		Open:   open,
+		High:   decimal.Max(open, p),
+		Low:    decimal.Min(open, p),
+		Close:  p,
+		Volume: t.vol,
+	}
+	t.vol = 0
+	if t.lines = append(t.lines, line); len(t.lines) > t.n {
+		t.lines = append(t.lines[:0], t.lines[1:]...)
+	}
+	return []*Candle{&line}
+}
@@ -0,0 +1,123 @@
+package ta
+
+import (
+	"testing"
+
+	"go.oneofone.dev/ta/decimal"
+)
+
+func closes(vs ...Decimal) []*Candle {
+	out := make([]*Candle, 0, len(vs))
+	for _, v := range vs {
+		out = append(out, &Candle{Open: v, High: v, Low: v, Close: v, Volume: 1})
+	}
+	return out
+}
+
+func checkBars(t *testing.T, name string, bars []*Candle, exp [][2]Decimal) {
+	t.Helper()
+	if len(bars) != len(exp) {
+		t.Fatalf("%s: expected %d bars, got %d", name, len(exp), len(bars))
+	}
+	for i, b := range bars {
+		if b.Open != exp[i][0] || b.Close != exp[i][1] {
+			t.Fatalf("%s [%d]: expected %v, got [%v %v]", name, i, exp[i], b.Open, b.Close)
+		}
+		if b.High != decimal.Max(b.Open, b.Close) || b.Low != decimal.Min(b.Open, b.Close) {
+			t.Fatalf("%s [%d]: bad high/low %+v", name, i, b)
+		}
+	}
+}
+
+func TestHeikinAshi(t *testing.T) {
+	ha := ApplyTransform(HeikinAshi(), []*Candle{
+		{Open: 10, High: 14, Low: 8, Close: 12, Volume: 5},
+		{Open: 12, High: 16, Low: 11, Close: 15, Volume: 7},
+	})
+	exp := []Candle{
+		{Open: 11, High: 14, Low: 8, Close: 11, Volume: 5},
+		{Open: 11, High: 16, Low: 11, Close: 13.5, Volume: 7},
+	}
+	for i := range exp {
+		if *ha[i] != exp[i] {
+			t.Fatalf("[%d] expected %+v, got %+v", i, exp[i], *ha[i])
+		}
+	}
+}
+
+func TestRenko(t *testing.T) {
+	bars := ApplyTransform(Renko(1), closes(100, 101, 102.5, 103.1, 101.9, 100.9, 99.9, 103.2))
+	checkBars(t, "renko", bars, [][2]Decimal{
+		{100, 101}, {101, 102}, {102, 103},
+		{102, 101}, {101, 100},
+		{101, 102}, {102, 103},
+	})
+	if bars[0].Volume != 2 || bars[1].Volume != 1 || bars[5].Volume != 1 || bars[6].Volume != 0 {
+		t.Fatalf("unexpected volumes: %+v", bars)
+	}
+
+	// the box is only known after period+1 candles
+	candles := testCandles()
+	if bars := ApplyTransform(RenkoATR(14), candles[:15]); len(bars) != 0 {
+		t.Fatalf("expected no bricks, got %v", bars)
+	}
+	if bars := ApplyTransform(RenkoATR(14), candles); len(bars) == 0 {
+		t.Fatal("expected some bricks")
+	}
+}
+
+func TestKagi(t *testing.T) {
+	bars := ApplyTransform(Kagi(2, false), closes(10, 11, 13, 12, 10.9, 10, 11.9, 12))
+	checkBars(t, "kagi", bars, [][2]Decimal{{10, 13}, {13, 10}})
+
+	bars = ApplyTransform(Kagi(10, true), closes(100, 120, 107, 108, 90))
+	checkBars(t, "kagi%", bars, [][2]Decimal{{100, 120}})
+}
+
+func TestPointAndFigure(t *testing.T) {
+	bars := ApplyTransform(PointAndFigure(1, 3), closes(10.2, 11.5, 13.4, 11, 9.8, 9.1, 8.9, 12.2))
+	checkBars(t, "p&f", bars, [][2]Decimal{{10, 13}, {12, 9}})
+}
+
+func TestLineBreak(t *testing.T) {
+	bars := ApplyTransform(LineBreak(3), closes(10, 11, 12, 11.5, 13, 10.5, 9.5, 9))
+	checkBars(t, "line break", bars, [][2]Decimal{{10, 11}, {11, 12}, {12, 13}, {12, 9.5}, {9.5, 9}})
+}
+
+func TestStreamFromTransform(t *testing.T) {
+	candles := testCandles()
+	for name, fn := range map[string]func() CandleTransform{
+		"ha":    HeikinAshi,
+		"renko": func() CandleTransform { return RenkoATR(14) },
+		"kagi":  func() CandleTransform { return Kagi(1, true) },
+		"p&f":   func() CandleTransform { return PointAndFigure(1, 3) },
+		"3lb":   func() CandleTransform { return LineBreak(3) },
+	} {
+		exp := ApplyTransform(fn(), candles)
+		s := StreamFromTransform(fn(), 1, true)
+		go func() {
+			for _, c := range candles {
+				s.Update(c)
+			}
+			s.Close()
+		}()
+
+		var got []*Candle
+		for c := range s.Chan() {
+			got = append(got, c)
+		}
+		if len(got) != len(exp) {
+			t.Fatalf("%s: expected %d bars, got %d", name, len(exp), len(got))
+		}
+		for i := range exp {
+			if *got[i] != *exp[i] {
+				t.Fatalf("%s [%d]: expected %+v, got %+v", name, i, *exp[i], *got[i])
+			}
+		}
+
+		// the bars can be fed to any study
+		if atr := ApplyCandleSlice(ATR(2), got)[0]; atr.Len() != decimal.Min(2, len(got)) {
+			t.Fatalf("%s: unexpected atr %v", name, atr)
+		}
+	}
+}
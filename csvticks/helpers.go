@@ -56,17 +56,24 @@ func (dt DateTime) MarshalJSON() ([]byte, error) {
 
 // Time tries to convert the DateTime to time.Time
 // rules:
-// - if it's a number, it tries to parse it as nanoseconds, milliseconds or seconds
-// - if it's a string it'll try to parse it with the default time fmt
+// - if it's a quoted string it'll parse it with the default time fmt
+// - if it's not quoted (csv files don't quote their dates), it'll try the default time fmt first
+// - then if it's a number, it tries to parse it as nanoseconds, milliseconds or seconds
 // see `SetDefaultTimeFormat`
 func (dt DateTime) Time() time.Time {
 	const ms = int64(1e12)
 	const ns = int64(1e15)
+	if len(dt) == 0 {
+		return time.Time{}
+	}
+	dtfmt, _ := defaultTimeFmt.Load().(string)
 	if dt[0] == '"' {
-		dtfmt, _ := defaultTimeFmt.Load().(string)
 		t, _ := time.Parse(dtfmt, string(dt[1:len(dt)-1]))
 		return t
 	}
+	if t, err := time.Parse(dtfmt, string(dt)); err == nil {
+		return t
+	}
 	n, _ := strconv.ParseInt(string(dt), 10, 64)
 
 	if n > ns {
@@ -225,3 +225,103 @@ func (tks Ticks) Close() *ta.TA {
 	}
 	return out
 }
+
+// Volume returns only Volume values as a TA
+func (tks Ticks) Volume() *ta.TA {
+	out := ta.NewSize(len(tks), true)
+	for _, t := range tks {
+		out.Append(ta.Decimal(t.Volume))
+	}
+	return out
+}
+
+// Candle returns the tick as a candle
+func (t *Tick) Candle() *ta.Candle {
+	return &ta.Candle{Open: t.Open, High: t.High, Low: t.Low, Close: t.Close, Volume: int(t.Volume)}
+}
+
+// Candles returns the ticks as candles
+func (tks Ticks) Candles() []*ta.Candle {
+	out := make([]*ta.Candle, 0, len(tks))
+	for _, t := range tks {
+		out = append(out, t.Candle())
+	}
+	return out
+}
+
+// ApplyTimedStudy applies the given study to the ticks using their timestamps and returns the result(s)
+func (tks Ticks) ApplyTimedStudy(s ta.TimedStudy) []*ta.TA {
+	out := make([]*ta.TA, len(s.LenAll()))
+	for i := range out {
+		out[i] = ta.NewSize(len(tks), true)
+	}
+	for _, t := range tks {
+		for i, v := range s.UpdateCandleAt(t.TS.Time(), t.Candle()) {
+			out[i].Append(v)
+		}
+	}
+	return out
+}
+
+// ApplyCandleStudy applies the given study to the ticks as candles and returns all the result(s)
+func (tks Ticks) ApplyCandleStudy(s ta.CandleStudy) []*ta.TA {
+	out := make([]*ta.TA, len(s.LenAll()))
+	for i := range out {
+		out[i] = ta.NewSize(len(tks), true)
+	}
+	for _, t := range tks {
+		for i, v := range s.UpdateCandle(t.Candle()) {
+			out[i].Append(v)
+		}
+	}
+	return out
+}
+
+// Eval parses the expression with ta.ParseExpr and returns its value for every tick
+func (tks Ticks) Eval(expr string) (*ta.TA, error) {
+	s, err := ta.ParseExpr(expr)
+	if err != nil {
+		return nil, err
+	}
+	return tks.ApplyCandleStudy(s)[0], nil
+}
+
+// Columns returns the ticks as candle columns
+func (tks Ticks) Columns() *ta.Candles {
+	return &ta.Candles{
+		Open:   tks.Open(),
+		High:   tks.High(),
+		Low:    tks.Low(),
+		Close:  tks.Close(),
+		Volume: tks.Volume(),
+	}
+}
+
+// Trade returns the tick as a trade, using the close as the price
+func (t *Tick) Trade() ta.Trade {
+	return ta.Trade{Time: t.TS.Time(), Price: t.Close, Size: int(t.Volume)}
+}
+
+// Trades returns the ticks as trades
+func (tks Ticks) Trades() []ta.Trade {
+	out := make([]ta.Trade, 0, len(tks))
+	for _, t := range tks {
+		out = append(out, t.Trade())
+	}
+	return out
+}
+
+// Bars aggregates the ticks into bars using a and flushes the forming bar at the end,
+// if a is a *ta.BarBuilder, ticks with a high and low are added as candles, otherwise every tick is a trade.
+func (tks Ticks) Bars(a ta.BarAggregator) ta.Bars {
+	b, _ := a.(*ta.BarBuilder)
+	var out ta.Bars
+	for _, t := range tks {
+		if b != nil && (t.High != 0 || t.Low != 0) {
+			out = append(out, b.UpdateCandle(t.TS.Time(), t.Candle())...)
+		} else {
+			out = append(out, a.Update(t.Trade())...)
+		}
+	}
+	return append(out, a.Flush()...)
+}
@@ -125,12 +125,30 @@ func (d Decimal) Log() Decimal {
 	return Decimal(v)
 }
 
+// Exp returns e**d
+func (d Decimal) Exp() Decimal {
+	v := math.Exp(float64(d))
+	return Decimal(v)
+}
+
 // Atan uses shopspring/decimal
 func (d Decimal) Atan() Decimal {
 	v := math.Atan(float64(d))
 	return Decimal(v)
 }
 
+// Sin returns the sine of the radian argument d
+func (d Decimal) Sin() Decimal {
+	v := math.Sin(float64(d))
+	return Decimal(v)
+}
+
+// Cos returns the cosine of the radian argument d
+func (d Decimal) Cos() Decimal {
+	v := math.Cos(float64(d))
+	return Decimal(v)
+}
+
 func (d Decimal) Floor(unit Decimal) Decimal {
 	if unit == 0 || unit == 1 {
 		return Decimal(math.Floor(d.Float()))
@@ -49,6 +49,9 @@ func SliceToFloats(in []Decimal, copy bool) []float64 {
 	return *(*[]float64)(unsafe.Pointer(&in))
 }
 
+// AggPipe averages the values from in every aggPeriod of wall clock time.
+//
+// Deprecated: use ta.BarPipe, it builds OHLCV bars using the time of each trade.
 func AggPipe(aggPeriod time.Duration, in <-chan Decimal) <-chan Decimal {
 	ch := make(chan Decimal, 100)
 	buf := make([]float64, 0, 600)
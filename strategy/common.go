@@ -11,25 +11,23 @@ func RSI(period, oversold, overbought int, fn ta.MovingAverageFunc) Strategy {
 		r = ta.RSIExt(fn(period))
 	}
 	return &rsi{
-		rsi:        r,
+		rsi:        ta.Readiness(r),
 		oversold:   Decimal(oversold),
 		overbought: Decimal(overbought),
-		idx:        period,
 	}
 }
 
 type rsi struct {
-	rsi        ta.Study
+	rsi        ta.ReadyStudy
 	oversold   Decimal
 	overbought Decimal
 	last       Decimal
-	idx        int
 	dir        int8
 }
 
 func (s *rsi) Setup(candles []*Candle) {
 	for _, c := range candles {
-		s.rsi.Update(c.Close)
+		s.last = s.rsi.Update(c.Close)
 	}
 }
 
@@ -37,8 +35,7 @@ func (s *rsi) Update(c *Candle) (buy, sell bool) {
 	v := c.Close
 	v = s.rsi.Update(v)
 	switch {
-	case s.idx > 0:
-		s.idx--
+	case !s.rsi.Ready():
 	case decimal.Crosover(v, s.last, s.overbought):
 		s.dir = 1
 	case decimal.Crossunder(v, s.last, s.oversold):
@@ -53,8 +50,7 @@ func (s *rsi) Update(c *Candle) (buy, sell bool) {
 
 func MACD(fastPeriod, slowPeriod, signalPeriod int) Strategy {
 	return &macd{
-		macd: ta.MACD(fastPeriod, slowPeriod, signalPeriod),
-		idx:  (fastPeriod + slowPeriod + signalPeriod) / 3,
+		macd: ta.Readiness(ta.MACD(fastPeriod, slowPeriod, signalPeriod)),
 	}
 }
 
@@ -67,8 +63,7 @@ func MACDWithResistance(resistance, fastPeriod, slowPeriod, signalPeriod int, fn
 		m = ta.MACDExt(fastPeriod, slowPeriod, signalPeriod, fn)
 	}
 	return &macd{
-		macd: m,
-		idx:  (fastPeriod + slowPeriod + signalPeriod) / 3,
+		macd: ta.Readiness(m),
 		res:  resistance,
 	}
 }
@@ -78,8 +73,7 @@ func MACDExt(resistance, fastPeriod, slowPeriod, signalPeriod int, fn ta.MovingA
 		resistance = 0
 	}
 	return &macd{
-		macd: ta.MACDExt(fastPeriod, slowPeriod, signalPeriod, fn),
-		idx:  (fastPeriod + slowPeriod + signalPeriod) / 3,
+		macd: ta.Readiness(ta.MACDExt(fastPeriod, slowPeriod, signalPeriod, fn)),
 		res:  resistance,
 	}
 }
@@ -89,23 +83,21 @@ func MACDMulti(resistance int, fast, slow, signal ta.MovingAverage) Strategy {
 		resistance = 0
 	}
 	return &macd{
-		macd: ta.MACDMulti(fast, slow, signal),
-		idx:  fast.Len() + slow.Len() + signal.Len()/3,
+		macd: ta.Readiness(ta.MACDMulti(fast, slow, signal)),
 		res:  resistance,
 	}
 }
 
 type macd struct {
-	macd ta.Study
+	macd ta.ReadyStudy
 	last Decimal
 	res  int
-	idx  int
 	dir  int
 }
 
 func (s *macd) Setup(candles []*Candle) {
 	for _, c := range candles {
-		s.macd.Update(c.Close)
+		s.last = s.macd.Update(c.Close)
 	}
 }
 
@@ -113,8 +105,7 @@ func (s *macd) Update(c *Candle) (buy, sell bool) {
 	v := s.macd.Update(c.Close)
 
 	switch {
-	case s.idx > 0:
-		s.idx--
+	case !s.macd.Ready():
 	case decimal.Crossunder(v, s.last, 0):
 		s.dir = -1
 	case decimal.Crosover(v, s.last, 0):
@@ -130,3 +121,27 @@ func (s *macd) Update(c *Candle) (buy, sell bool) {
 	s.last = v
 	return s.dir > s.res, s.dir < -s.res
 }
+
+// SAR buys when the Parabolic SAR flips to long and sells when it flips to short
+func SAR(accel, max Decimal) Strategy {
+	return &sar{sar: ta.SAR(accel, max)}
+}
+
+type sar struct {
+	sar ta.CandleStudy
+	dir Decimal
+}
+
+func (s *sar) Setup(candles []*Candle) {
+	if last := ta.SetupCandles(s.sar, candles); last != nil {
+		s.dir = last[1]
+	}
+}
+
+func (s *sar) Update(c *Candle) (buy, sell bool) {
+	dir := s.sar.UpdateCandle(c)[1]
+	// the first direction isn't a reversal
+	flip := s.dir != 0 && dir != s.dir
+	s.dir = dir
+	return flip && dir > 0, flip && dir < 0
+}
@@ -0,0 +1,40 @@
+package strategy
+
+import (
+	"go.oneofone.dev/ta"
+)
+
+// CandlePatterns wraps s and only lets its signals through if they're confirmed by a candle pattern on the same candle,
+// a buy needs a bullish (> 0) pattern and a sell needs a bearish (< 0) one.
+// if cs is nil, ta.DefaultCandleSettings is used, if no patterns are given, all the patterns are used.
+func CandlePatterns(s Strategy, cs *ta.CandleSettings, ps ...ta.CandlePattern) Strategy {
+	if len(ps) == 0 {
+		ps = ta.AllCandlePatterns()
+	}
+	return &patterns{
+		s:   s,
+		cdl: ta.CandlePatterns(cs, ps...),
+	}
+}
+
+type patterns struct {
+	s   Strategy
+	cdl ta.CandleStudy
+}
+
+func (s *patterns) Setup(candles []*Candle) {
+	s.s.Setup(candles)
+	ta.SetupCandles(s.cdl, candles)
+}
+
+func (s *patterns) Update(c *Candle) (buy, sell bool) {
+	buy, sell = s.s.Update(c)
+
+	var bull, bear bool
+	for _, v := range s.cdl.UpdateCandle(c) {
+		bull = bull || v > 0
+		bear = bear || v < 0
+	}
+
+	return buy && bull, sell && bear
+}
@@ -3,6 +3,7 @@ package strategy
 import (
 	"log"
 
+	"go.oneofone.dev/ta"
 	"go.oneofone.dev/ta/csvticks"
 	"go.oneofone.dev/ta/decimal"
 )
@@ -14,13 +15,7 @@ type Engine interface {
 	Stop() (shares int, pricePershare, availableBalance Decimal)
 }
 
-type Candle struct {
-	Open   Decimal
-	High   Decimal
-	Low    Decimal
-	Close  Decimal
-	Volume int
-}
+type Candle = ta.Candle
 
 type Strategy interface {
 	Setup(candles []*Candle)
@@ -55,7 +50,7 @@ func ApplySlice(acc Account, str Strategy, symbol string, data csvticks.Ticks) *
 	inp := make(chan *Candle, 1)
 	go func() {
 		for _, t := range data {
-			inp <- &Candle{Close: t.Close, Volume: int(t.Volume), Open: t.Open, High: t.High, Low: t.Low}
+			inp <- t.Candle()
 		}
 		close(inp)
 	}()
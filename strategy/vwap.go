@@ -7,29 +7,27 @@ import (
 
 func VWAP(up, down Decimal) Strategy {
 	return &vwap{
-		vwap: ta.VWAPBands(up, down),
-		idx:  int(decimal.Max(up, down)),
+		vwap: ta.Readiness(ta.VWAPBands(up, down)),
 	}
 }
 
 type vwap struct {
-	vwap   ta.MultiVarStudy
+	vwap   ta.ReadyStudy
 	up, dn Decimal
-	idx    int
 	dir    int8
 }
 
 func (s *vwap) Setup(candles []*Candle) {
 	for _, c := range candles {
-		s.vwap.Update(c.Close)
+		v := s.vwap.UpdateAll(Decimal(c.Volume), c.Close)
+		s.up, s.dn = v[1], v[2]
 	}
 }
 
 func (s *vwap) Update(c *Candle) (buy, sell bool) {
 	v := s.vwap.UpdateAll(Decimal(c.Volume), c.Close)
 	switch {
-	case s.idx > 0:
-		s.idx--
+	case !s.vwap.Ready():
 	case decimal.Crosover(v[0], s.up, v[1]):
 		s.dir = 1
 	case decimal.Crossunder(v[0], s.dn, v[2]):
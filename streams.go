@@ -43,13 +43,18 @@ func (a *studyStream) Close() {
 	close(a.ch)
 }
 
+// Aggregate returns a stream that sends the average of every period values.
+//
+// Deprecated: use BarBuilder to build OHLCV bars.
 func Aggregate(period int, blockOnFull bool) Stream {
 	return AggregateFn((*TA).Avg, period, blockOnFull)
 }
 
+// AggregateFn returns a stream that sends fn of every period values
 func AggregateFn(fn AggFunc, period int, blockOnFull bool) Stream {
 	return &agg{
 		d:     NewSize(period, true),
+		fn:    fn,
 		ch:    make(chan Decimal, period),
 		block: blockOnFull,
 	}
@@ -90,3 +95,92 @@ func (a *agg) Chan() <-chan Decimal {
 func (a *agg) Close() {
 	close(a.ch)
 }
+
+// CandleStream is the candle version of Stream
+type CandleStream interface {
+	Chan() <-chan *Candle
+	Update(c *Candle)
+	Close()
+}
+
+// StreamFromTransform returns a stream that sends every bar completed by t,
+// size is the buffer size of the channel.
+func StreamFromTransform(t CandleTransform, size int, blockOnFull bool) CandleStream {
+	return &transformStream{
+		t:     t,
+		ch:    make(chan *Candle, size),
+		block: blockOnFull,
+	}
+}
+
+type transformStream struct {
+	t  CandleTransform
+	ch chan *Candle
+
+	block bool
+}
+
+func (a *transformStream) Update(c *Candle) {
+	for _, c := range a.t.Update(c) {
+		if a.block {
+			a.ch <- c
+		} else {
+			select {
+			case a.ch <- c:
+			default:
+			}
+		}
+	}
+}
+
+func (a *transformStream) Chan() <-chan *Candle {
+	return a.ch
+}
+
+func (a *transformStream) Close() {
+	close(a.ch)
+}
+
+// CandleStudyStream is a stream that feeds candles to a study and sends its values
+type CandleStudyStream interface {
+	Chan() <-chan Decimal
+	Update(c *Candle)
+	Close()
+}
+
+// StreamFromCandleStudy returns a stream that sends the first value of s.UpdateCandle for every candle,
+// for example the value of an expression returned by ParseExpr, size is the buffer size of the channel.
+func StreamFromCandleStudy(s CandleStudy, size int, blockOnFull bool) CandleStudyStream {
+	return &candleStudyStream{
+		s:     s,
+		ch:    make(chan Decimal, size),
+		block: blockOnFull,
+	}
+}
+
+type candleStudyStream struct {
+	s  CandleStudy
+	ch chan Decimal
+
+	block bool
+}
+
+func (a *candleStudyStream) Update(c *Candle) {
+	v := a.s.UpdateCandle(c)[0]
+	if a.block {
+		a.ch <- v
+	} else {
+		select {
+		case a.ch <- v:
+		default:
+		}
+	}
+}
+
+func (a *candleStudyStream) Chan() <-chan Decimal {
+	return a.ch
+}
+
+func (a *candleStudyStream) Close() {
+	close(a.ch)
+}
@@ -0,0 +1,28 @@
+package ta
+
+import "testing"
+
+func TestAggregateFn(t *testing.T) {
+	for _, c := range []struct {
+		name string
+		s    Stream
+		exp  []Decimal
+	}{
+		{"avg", Aggregate(3, false), []Decimal{2, 5}},
+		{"max", AggregateFn((*TA).Max, 3, false), []Decimal{3, 6}},
+		{"sum", AggregateFn((*TA).Sum, 3, false), []Decimal{6, 15}},
+	} {
+		for v := Decimal(1); v <= 7; v++ {
+			c.s.Update(v)
+		}
+		c.s.Close()
+
+		var got []Decimal
+		for v := range c.s.Chan() {
+			got = append(got, v)
+		}
+		if !sameBits(c.exp, got) {
+			t.Fatalf("%s: expected %v, got %v", c.name, c.exp, got)
+		}
+	}
+}
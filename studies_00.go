@@ -3,6 +3,8 @@ package ta
 import (
 	"math"
 	"sync"
+
+	"go.oneofone.dev/ta/decimal"
 )
 
 // LockedStudy returns a thread-safe version of the study
@@ -38,6 +40,25 @@ func (l *locked) ToMulti() (s MultiVarStudy, ok bool) {
 	return
 }
 
+func (l *locked) Lookback() int { return Lookback(l.Study) }
+
+func (l *locked) Clone() Study {
+	l.m.Lock()
+	defer l.m.Unlock()
+	return clone(l)
+}
+
+func (l *locked) Reset() {
+	l.m.Lock()
+	l.Study.Reset()
+	l.m.Unlock()
+}
+
+func (l *locked) lockState() func() {
+	l.m.Lock()
+	return l.m.Unlock
+}
+
 type lockedMulti struct {
 	MultiVarStudy
 	m sync.Mutex
@@ -50,6 +71,25 @@ func (l *lockedMulti) UpdateAll(vs ...Decimal) []Decimal {
 	return vs
 }
 
+func (l *lockedMulti) Lookback() int { return Lookback(l.MultiVarStudy) }
+
+func (l *lockedMulti) Clone() Study {
+	l.m.Lock()
+	defer l.m.Unlock()
+	return clone(l)
+}
+
+func (l *lockedMulti) Reset() {
+	l.m.Lock()
+	l.MultiVarStudy.Reset()
+	l.m.Unlock()
+}
+
+func (l *lockedMulti) lockState() func() {
+	l.m.Lock()
+	return l.m.Unlock
+}
+
 func (l *lockedMulti) ToStudy() (s Study, ok bool) {
 	l.m.Lock()
 	defer l.m.Unlock()
@@ -72,6 +112,14 @@ type Study interface {
 
 	// ToMulti can be used to convert the study to a multi-variable study if it's supported
 	ToMulti() (MultiVarStudy, bool)
+
+	// Clone returns a deep copy of the study, including any nested studies and the data it has seen so far,
+	// the copy and the original can be updated independently.
+	// The clone of a MultiVarStudy is also a MultiVarStudy.
+	Clone() Study
+
+	// Reset returns the study to the state it was created in, as if it never had any updates
+	Reset()
 }
 
 // StudyWithSetup is a study that supports a setup function for the initial data set
@@ -105,8 +153,18 @@ type MultiVarStudy interface {
 	ToStudy() (Study, bool)
 }
 
+// DisplacedStudy is a study with outputs that belong to a different bar than the one they were calculated on,
+// for example Ichimoku's cloud
+type DisplacedStudy interface {
+	MultiVarStudy
+
+	// Displacement returns how many bars each output of UpdateAll is shifted by,
+	// positive values are ahead of the current bar and negative ones are behind it
+	Displacement() []int
+}
+
 // ApplyStudy applies the given study to the input(s) and returns the result(s)
-// the returned TA.Len() == s.Len()
+// the returned TA.Len() == s.Len(), studies without a period (s.Len() < 1) return all the values
 func ApplyStudy(s Study, tas ...*TA) *TA {
 	if sws, ok := s.(StudyWithSetup); ok {
 		return sws.Setup(tas...)[0]
@@ -115,6 +173,9 @@ func ApplyStudy(s Study, tas ...*TA) *TA {
 	vals := make([]Decimal, len(tas))
 	ln := tas[0].Len()
 	sln := s.Len()
+	if sln < 1 {
+		sln = ln
+	}
 	out := NewSize(sln, true)
 
 	for i := 0; i < ln; i++ {
@@ -131,16 +192,17 @@ func ApplyStudy(s Study, tas ...*TA) *TA {
 }
 
 // ApplyMultiVarStudy applies the given study to input(s) and returns the result(s)
-// the returned TA[x].Len() == s.LenAll()[x]
+// the returned TA[x].Len() == s.LenAll()[x], or all the values if s.LenAll()[x] < 1
+// the outputs of a DisplacedStudy are shifted to the bar they belong to
 func ApplyMultiVarStudy(s MultiVarStudy, tas ...*TA) []*TA {
 	if sws, ok := s.(StudyWithSetup); ok {
 		return sws.Setup(tas...)
 	}
 
-	slen := s.LenAll()
+	ln := tas[0].Len()
+	slen := outputLens(s, ln)
 	out := make([]*TA, len(slen))
 	vals := make([]Decimal, len(tas))
-	ln := tas[0].Len()
 
 	for i := 0; i < len(slen); i++ {
 		out[i] = NewSize(slen[i], true)
@@ -157,9 +219,50 @@ func ApplyMultiVarStudy(s MultiVarStudy, tas ...*TA) []*TA {
 		}
 	}
 
+	return displace(s, out)
+}
+
+// displace shifts the outputs of a DisplacedStudy to the bars they belong to,
+// an output displaced n bars ahead starts with n NaNs and is n values longer, the extra values are past the last bar,
+// an output displaced n bars behind drops its first n values and ends with n NaNs.
+func displace(s MultiVarStudy, out []*TA) []*TA {
+	ds, ok := s.(DisplacedStudy)
+	if !ok {
+		return out
+	}
+	nan := Decimal(math.NaN())
+	for i, n := range ds.Displacement() {
+		ta := out[i]
+		switch {
+		case n > 0:
+			shifted := NewSize(ta.Len()+n, true)
+			for j := 0; j < n; j++ {
+				shifted.Append(nan)
+			}
+			out[i] = shifted.Append(ta.v...)
+		case n < 0:
+			n = decimal.Min(-n, ta.Len())
+			shifted := NewSize(ta.Len(), true).Append(ta.v[n:]...)
+			for j := 0; j < n; j++ {
+				shifted.Append(nan)
+			}
+			out[i] = shifted
+		}
+	}
 	return out
 }
 
+// outputLens returns s.LenAll(), replacing any length < 1 with ln
+func outputLens(s MultiVarStudy, ln int) []int {
+	slen := append([]int(nil), s.LenAll()...)
+	for i, n := range slen {
+		if n < 1 {
+			slen[i] = ln
+		}
+	}
+	return slen
+}
+
 // RSI - Relative Strength Index
 func RSI(period int) Study {
 	checkPeriod(period, 2)
@@ -180,6 +283,16 @@ func RSIExt(ma MovingAverage) Study {
 	}
 }
 
+// CMO - Chande Momentum Oscillator, same as RSI except it returns 100 * (up - down) / (up + down)
+func CMO(period int) Study {
+	checkPeriod(period, 2)
+	return &rsi{
+		period: period,
+		per:    1 / Decimal(period),
+		cmo:    true,
+	}
+}
+
 var _ Study = (*rsi)(nil)
 
 type rsi struct {
@@ -191,6 +304,7 @@ type rsi struct {
 	per        Decimal
 	period     int
 	idx        int
+	cmo        bool
 }
 
 func (l *rsi) Update(vs ...Decimal) Decimal {
@@ -234,6 +348,12 @@ func (l *rsi) Update(vs ...Decimal) Decimal {
 	}
 
 	upDown := l.smoothUp + l.smoothDown
+	if l.cmo {
+		if isZero(upDown) {
+			return 0
+		}
+		return 100 * ((l.smoothUp - l.smoothDown) / upDown)
+	}
 	if upDown == 0 {
 		return l.prev
 	}
@@ -243,6 +363,22 @@ func (l *rsi) Update(vs ...Decimal) Decimal {
 
 func (l *rsi) Len() int { return l.period }
 
+func (l *rsi) Lookback() int {
+	if l.ext != nil {
+		return Lookback(l.ext) + l.period
+	}
+	return l.period
+}
+
+func (l *rsi) Clone() Study { return clone(l) }
+
+func (l *rsi) Reset() {
+	if l.ext != nil {
+		l.ext.Reset()
+	}
+	*l = rsi{ext: l.ext, per: l.per, period: l.period, cmo: l.cmo}
+}
+
 // MACD - Moving Average Convergence/Divergence, using EMA for all periods
 // alias for MACDExt(fastPeriod, slowPeriod, signalPeriod, EMA)
 func MACD(fastPeriod, slowPeriod, signalPeriod int) MultiVarStudy {
@@ -317,6 +453,19 @@ func (l *macd) UpdateAll(vs ...Decimal) []Decimal {
 }
 
 func (l *macd) Len() int { return l.signal.Len() }
+
+func (l *macd) Lookback() int {
+	return decimal.Max(Lookback(l.fast), Lookback(l.slow)) + Lookback(l.signal)
+}
+
+func (l *macd) Clone() Study { return clone(l) }
+
+func (l *macd) Reset() {
+	l.fast.Reset()
+	l.slow.Reset()
+	l.signal.Reset()
+	l.prev = math.MaxFloat64
+}
 func (l *macd) LenAll() []int {
 	return []int{l.slow.Len(), l.fast.Len(), l.signal.Len()}
 }
@@ -412,6 +561,14 @@ func (l *vwap) UpdateAll(vs ...Decimal) []Decimal {
 
 func (l *vwap) Len() int      { return l.std.Len() }
 func (l *vwap) LenAll() []int { return []int{l.std.Len()} }
+func (l *vwap) Lookback() int { return l.std.Lookback() }
+
+func (l *vwap) Clone() Study { return clone(l) }
+
+func (l *vwap) Reset() {
+	l.std.Reset()
+	l.sum, l.total = 0, 0
+}
 
 func (l *vwap) ToMulti() (MultiVarStudy, bool) { return l, true }
 func (l *vwap) ToStudy() (Study, bool)         { return l, true }
@@ -1,5 +1,7 @@
 package ta
 
+import "go.oneofone.dev/ta/decimal"
+
 // MinMax returns the min/max over a period
 // Update returns min
 // UpdateAll returns [min, max]
@@ -33,7 +35,10 @@ func (s *minmax) UpdateAll(vs ...Decimal) []Decimal {
 	return []Decimal{ta.Min(), ta.Max()}
 }
 
-func (s *minmax) Len() int { return s.data.Len() }
+func (s *minmax) Len() int      { return s.data.Len() }
+func (s *minmax) Lookback() int { return s.data.Cap() - 1 }
+func (s *minmax) Clone() Study  { return clone(s) }
+func (s *minmax) Reset()        { s.data = NewCapped(s.data.Cap()) }
 func (s *minmax) LenAll() []int {
 	ln := s.Len()
 	return []int{ln, ln}
@@ -98,6 +103,22 @@ func (s *bbands) UpdateAll(vs ...Decimal) []Decimal {
 }
 
 func (s *bbands) Len() int { return s.std.Len() }
+
+func (s *bbands) Lookback() int {
+	if s.ext != nil {
+		return decimal.Max(s.std.Lookback(), Lookback(s.ext))
+	}
+	return s.std.Lookback()
+}
+
+func (s *bbands) Clone() Study { return clone(s) }
+
+func (s *bbands) Reset() {
+	s.std.Reset()
+	if s.ext != nil {
+		s.ext.Reset()
+	}
+}
 func (s *bbands) LenAll() []int {
 	ln := s.Len()
 	return []int{ln, ln, ln}
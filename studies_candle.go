@@ -0,0 +1,243 @@
+package ta
+
+import "go.oneofone.dev/ta/decimal"
+
+// CandleStudy represents a study that works on full candles rather than positional values
+// Update/UpdateAll still work, however each call is treated as a single candle, the supported layouts are:
+//
+//	1 value:  [price], used as open, high, low and close
+//	2 values: [volume, price], same as VWAP
+//	3 values: [high, low, close]
+//	4 values: [open, high, low, close]
+//	5 values: [open, high, low, close, volume]
+//
+// it will panic otherwise
+type CandleStudy interface {
+	MultiVarStudy
+
+	// UpdateCandle same as `UpdateAll` but takes a candle
+	UpdateCandle(c *Candle) []Decimal
+}
+
+// CandleField returns a single value from a candle, used to drive single input studies from candles
+type CandleField func(c *Candle) Decimal
+
+var (
+	FieldOpen   CandleField = func(c *Candle) Decimal { return c.Open }
+	FieldHigh   CandleField = func(c *Candle) Decimal { return c.High }
+	FieldLow    CandleField = func(c *Candle) Decimal { return c.Low }
+	FieldClose  CandleField = func(c *Candle) Decimal { return c.Close }
+	FieldVolume CandleField = func(c *Candle) Decimal { return Decimal(c.Volume) }
+	FieldHL2    CandleField = (*Candle).HL2
+	FieldHLC3   CandleField = (*Candle).HLC3
+	FieldOHLC4  CandleField = (*Candle).OHLC4
+)
+
+// HL2 returns (high + low) / 2
+func (c *Candle) HL2() Decimal { return (c.High + c.Low) / 2 }
+
+// HLC3 returns (high + low + close) / 3
+func (c *Candle) HLC3() Decimal { return (c.High + c.Low + c.Close) / 3 }
+
+// OHLC4 returns (open + high + low + close) / 4
+func (c *Candle) OHLC4() Decimal { return (c.Open + c.High + c.Low + c.Close) / 4 }
+
+// Values returns the candle as [open, high, low, close, volume]
+func (c *Candle) Values() []Decimal {
+	return []Decimal{c.Open, c.High, c.Low, c.Close, Decimal(c.Volume)}
+}
+
+// bar is the internal version of Candle, it keeps the volume as a Decimal
+type bar struct {
+	open, high, low, close, volume Decimal
+}
+
+func barFromValues(vs []Decimal) bar {
+	switch len(vs) {
+	case 1:
+		return bar{vs[0], vs[0], vs[0], vs[0], 0}
+	case 2:
+		return bar{vs[1], vs[1], vs[1], vs[1], vs[0]}
+	case 3:
+		return bar{vs[2], vs[0], vs[1], vs[2], 0}
+	case 4:
+		return bar{vs[0], vs[1], vs[2], vs[3], 0}
+	case 5:
+		return bar{vs[0], vs[1], vs[2], vs[3], vs[4]}
+	default:
+		panic("candle: expected [price], [volume, price], [high, low, close], [open, high, low, close] or [open, high, low, close, volume]")
+	}
+}
+
+func barFromCandle(c *Candle) bar {
+	return bar{c.Open, c.High, c.Low, c.Close, Decimal(c.Volume)}
+}
+
+func (b *bar) candle() *Candle {
+	return &Candle{Open: b.open, High: b.high, Low: b.low, Close: b.close, Volume: int(b.volume)}
+}
+
+func (b *bar) hl2() Decimal { return (b.high + b.low) / 2 }
+
+// trueRange returns the true range of the bar given the previous close
+func (b *bar) trueRange(prevClose Decimal) Decimal {
+	return decimal.Max(b.high, prevClose) - decimal.Min(b.low, prevClose)
+}
+
+// merge adds the next bar o to b, keeping the open of b
+func (b *bar) merge(o bar) {
+	b.high = decimal.Max(b.high, o.high)
+	b.low = decimal.Min(b.low, o.low)
+	b.close = o.close
+	b.volume += o.volume
+}
+
+// OnCandles returns a candle study that feeds the given field of every candle to s
+// if s supports `ToMulti`, UpdateAll will return all of its values
+func OnCandles(s Study, field CandleField) CandleStudy {
+	if field == nil {
+		field = FieldClose
+	}
+	oc := &onCandles{s: s, field: field}
+	oc.m, _ = s.ToMulti()
+	return oc
+}
+
+var _ CandleStudy = (*onCandles)(nil)
+
+type onCandles struct {
+	s     Study
+	m     MultiVarStudy
+	field CandleField
+}
+
+func (s *onCandles) Update(vs ...Decimal) Decimal {
+	b := barFromValues(vs)
+	return s.s.Update(s.field(b.candle()))
+}
+
+func (s *onCandles) UpdateAll(vs ...Decimal) []Decimal {
+	b := barFromValues(vs)
+	return s.UpdateCandle(b.candle())
+}
+
+func (s *onCandles) UpdateCandle(c *Candle) []Decimal {
+	if s.m != nil {
+		return s.m.UpdateAll(s.field(c))
+	}
+	return []Decimal{s.s.Update(s.field(c))}
+}
+
+func (s *onCandles) Len() int      { return s.s.Len() }
+func (s *onCandles) Lookback() int { return Lookback(s.s) }
+func (s *onCandles) Clone() Study  { return clone(s) }
+func (s *onCandles) Reset()        { s.s.Reset() }
+func (s *onCandles) LenAll() []int {
+	if s.m != nil {
+		return s.m.LenAll()
+	}
+	return []int{s.s.Len()}
+}
+
+func (s *onCandles) ToStudy() (Study, bool)         { return s, true }
+func (s *onCandles) ToMulti() (MultiVarStudy, bool) { return s, true }
+
+// Candles is a column based view of candles, all columns must have the same length,
+// any of them can be nil except Close, nil Open, High and Low columns use Close instead.
+type Candles struct {
+	Open   *TA
+	High   *TA
+	Low    *TA
+	Close  *TA
+	Volume *TA
+}
+
+// CandlesFromSlice converts a slice of candles to columns
+func CandlesFromSlice(candles []*Candle) *Candles {
+	cs := &Candles{
+		Open:   NewSize(len(candles), true),
+		High:   NewSize(len(candles), true),
+		Low:    NewSize(len(candles), true),
+		Close:  NewSize(len(candles), true),
+		Volume: NewSize(len(candles), true),
+	}
+	for _, c := range candles {
+		cs.Open.Append(c.Open)
+		cs.High.Append(c.High)
+		cs.Low.Append(c.Low)
+		cs.Close.Append(c.Close)
+		cs.Volume.Append(Decimal(c.Volume))
+	}
+	return cs
+}
+
+func (cs *Candles) Len() int { return cs.Close.Len() }
+
+func (cs *Candles) bar(i int) bar {
+	c := cs.Close.Get(i)
+	b := bar{c, c, c, c, 0}
+	if cs.Open != nil {
+		b.open = cs.Open.Get(i)
+	}
+	if cs.High != nil {
+		b.high = cs.High.Get(i)
+	}
+	if cs.Low != nil {
+		b.low = cs.Low.Get(i)
+	}
+	if cs.Volume != nil {
+		b.volume = cs.Volume.Get(i)
+	}
+	return b
+}
+
+// Candle returns the candle at index i
+func (cs *Candles) Candle(i int) *Candle {
+	b := cs.bar(i)
+	return b.candle()
+}
+
+// ApplyCandles applies the given study to the candle columns and returns the result(s)
+// the returned TA[x].Len() == s.LenAll()[x], or all the values if s.LenAll()[x] < 1
+// the outputs of a DisplacedStudy are shifted to the bar they belong to
+func ApplyCandles(s CandleStudy, cs *Candles) []*TA {
+	return applyCandles(s, cs.Len(), func(i int) []Decimal {
+		b := cs.bar(i)
+		return s.UpdateAll(b.open, b.high, b.low, b.close, b.volume)
+	})
+}
+
+// ApplyCandleSlice applies the given study to the candles and returns the result(s)
+// the returned TA[x].Len() == s.LenAll()[x], or all the values if s.LenAll()[x] < 1
+func ApplyCandleSlice(s CandleStudy, candles []*Candle) []*TA {
+	return applyCandles(s, len(candles), func(i int) []Decimal {
+		return s.UpdateCandle(candles[i])
+	})
+}
+
+func applyCandles(s CandleStudy, ln int, fn func(i int) []Decimal) []*TA {
+	slen := outputLens(s, ln)
+	out := make([]*TA, len(slen))
+	for i := range slen {
+		out[i] = NewSize(slen[i], true)
+	}
+
+	for i := 0; i < ln; i++ {
+		for j, v := range fn(i) {
+			if ln-i <= slen[j] {
+				out[j].Append(v)
+			}
+		}
+	}
+
+	return displace(s, out)
+}
+
+// SetupCandles feeds the candles to the study and returns the last result,
+// useful to warm up a study with historical data before feeding it live data
+func SetupCandles(s CandleStudy, candles []*Candle) (last []Decimal) {
+	for _, c := range candles {
+		last = s.UpdateCandle(c)
+	}
+	return
+}
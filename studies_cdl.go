@@ -0,0 +1,427 @@
+package ta
+
+import "strings"
+
+// CandleRangeType is the part of the candle a CandleSetting is measured against
+type CandleRangeType uint8
+
+const (
+	// RangeRealBody |close - open|
+	RangeRealBody CandleRangeType = iota
+	// RangeHighLow high - low
+	RangeHighLow
+	// RangeShadows upper shadow + lower shadow
+	RangeShadows
+)
+
+// CandleSetting defines what "long", "short", "near", etc mean for candle patterns,
+// the value is Factor * the average range of the AvgPeriod candles before the one being checked,
+// if AvgPeriod is 0, the range of the candle itself is used.
+type CandleSetting struct {
+	Range     CandleRangeType
+	AvgPeriod int
+	Factor    Decimal
+}
+
+// CandleSettings are the thresholds used by the candle patterns, same as TA-Lib's candle settings
+type CandleSettings struct {
+	BodyLong        CandleSetting
+	BodyVeryLong    CandleSetting
+	BodyShort       CandleSetting
+	BodyDoji        CandleSetting
+	ShadowLong      CandleSetting
+	ShadowVeryLong  CandleSetting
+	ShadowShort     CandleSetting
+	ShadowVeryShort CandleSetting
+	Near            CandleSetting
+	Far             CandleSetting
+	Equal           CandleSetting
+
+	// Penetration is used by the patterns that take TA-Lib's optInPenetration,
+	// if 0, the TA-Lib default of the pattern is used (0.3 or 0.5).
+	Penetration Decimal
+}
+
+// DefaultCandleSettings returns TA-Lib's default candle settings
+func DefaultCandleSettings() *CandleSettings {
+	return &CandleSettings{
+		BodyLong:        CandleSetting{RangeRealBody, 10, 1},
+		BodyVeryLong:    CandleSetting{RangeRealBody, 10, 3},
+		BodyShort:       CandleSetting{RangeRealBody, 10, 1},
+		BodyDoji:        CandleSetting{RangeHighLow, 10, 0.1},
+		ShadowLong:      CandleSetting{RangeRealBody, 0, 1},
+		ShadowVeryLong:  CandleSetting{RangeRealBody, 0, 2},
+		ShadowShort:     CandleSetting{RangeShadows, 10, 1},
+		ShadowVeryShort: CandleSetting{RangeHighLow, 10, 0.1},
+		Near:            CandleSetting{RangeHighLow, 5, 0.2},
+		Far:             CandleSetting{RangeHighLow, 5, 0.6},
+		Equal:           CandleSetting{RangeHighLow, 5, 0.05},
+	}
+}
+
+type cdlSet uint8
+
+const (
+	setBodyLong cdlSet = iota
+	setBodyVeryLong
+	setBodyShort
+	setBodyDoji
+	setShadowLong
+	setShadowVeryLong
+	setShadowShort
+	setShadowVeryShort
+	setNear
+	setFar
+	setEqual
+)
+
+func (cs *CandleSettings) get(s cdlSet) *CandleSetting {
+	switch s {
+	case setBodyLong:
+		return &cs.BodyLong
+	case setBodyVeryLong:
+		return &cs.BodyVeryLong
+	case setBodyShort:
+		return &cs.BodyShort
+	case setBodyDoji:
+		return &cs.BodyDoji
+	case setShadowLong:
+		return &cs.ShadowLong
+	case setShadowVeryLong:
+		return &cs.ShadowVeryLong
+	case setShadowShort:
+		return &cs.ShadowShort
+	case setShadowVeryShort:
+		return &cs.ShadowVeryShort
+	case setNear:
+		return &cs.Near
+	case setFar:
+		return &cs.Far
+	default:
+		return &cs.Equal
+	}
+}
+
+// CandlePattern is one of TA-Lib's CDL* candle patterns
+type CandlePattern uint8
+
+const (
+	CDL2Crows CandlePattern = iota
+	CDL3BlackCrows
+	CDL3Inside
+	CDL3LineStrike
+	CDL3Outside
+	CDL3StarsInSouth
+	CDL3WhiteSoldiers
+	CDLAbandonedBaby
+	CDLAdvanceBlock
+	CDLBeltHold
+	CDLBreakaway
+	CDLClosingMarubozu
+	CDLConcealBabySwall
+	CDLCounterAttack
+	CDLDarkCloudCover
+	CDLDoji
+	CDLDojiStar
+	CDLDragonflyDoji
+	CDLEngulfing
+	CDLEveningDojiStar
+	CDLEveningStar
+	CDLGapSideSideWhite
+	CDLGravestoneDoji
+	CDLHammer
+	CDLHangingMan
+	CDLHarami
+	CDLHaramiCross
+	CDLHighWave
+	CDLHikkake
+	CDLHikkakeMod
+	CDLHomingPigeon
+	CDLIdentical3Crows
+	CDLInNeck
+	CDLInvertedHammer
+	CDLKicking
+	CDLKickingByLength
+	CDLLadderBottom
+	CDLLongLeggedDoji
+	CDLLongLine
+	CDLMarubozu
+	CDLMatchingLow
+	CDLMatHold
+	CDLMorningDojiStar
+	CDLMorningStar
+	CDLOnNeck
+	CDLPiercing
+	CDLRickshawMan
+	CDLRiseFall3Methods
+	CDLSeparatingLines
+	CDLShootingStar
+	CDLShortLine
+	CDLSpinningTop
+	CDLStalledPattern
+	CDLStickSandwich
+	CDLTakuri
+	CDLTasukiGap
+	CDLThrusting
+	CDLTristar
+	CDLUnique3River
+	CDLUpsideGap2Crows
+	CDLXSideGap3Methods
+
+	numCandlePatterns
+)
+
+// String returns the TA-Lib name of the pattern, e.g. CDLDOJI
+func (p CandlePattern) String() string {
+	if p >= numCandlePatterns {
+		return "CDLUNKNOWN"
+	}
+	return cdlDefs[p].name
+}
+
+// AllCandlePatterns returns all the supported candle patterns
+func AllCandlePatterns() []CandlePattern {
+	out := make([]CandlePattern, numCandlePatterns)
+	for i := range out {
+		out[i] = CandlePattern(i)
+	}
+	return out
+}
+
+// ParseCandlePattern returns the pattern with the given TA-Lib name, the CDL prefix is optional and it's case insensitive
+func ParseCandlePattern(name string) (CandlePattern, bool) {
+	name = strings.ToUpper(name)
+	if !strings.HasPrefix(name, "CDL") {
+		name = "CDL" + name
+	}
+	for i := range cdlDefs {
+		if cdlDefs[i].name == name {
+			return CandlePattern(i), true
+		}
+	}
+	return 0, false
+}
+
+// Lookback returns the number of candles needed before the pattern returns anything, same as TA-Lib
+func (p CandlePattern) Lookback(cs *CandleSettings) int {
+	if cs == nil {
+		cs = DefaultCandleSettings()
+	}
+	d := &cdlDefs[p]
+	lb := 0
+	for _, s := range d.sets {
+		if n := cs.get(s).AvgPeriod; n > lb {
+			lb = n
+		}
+	}
+	return lb + d.extra
+}
+
+// CandlePatterns returns a study that checks every candle against the given patterns,
+// each pattern returns TA-Lib's signal, 100 (bullish), -100 (bearish) or 0,
+// some patterns return 80 for weaker matches (engulfing, harami) or 200 for confirmations (hikkake),
+// if cs is nil, DefaultCandleSettings is used.
+// Update returns the first non-zero signal
+// UpdateAll returns the signal of each pattern
+func CandlePatterns(cs *CandleSettings, ps ...CandlePattern) CandleStudy {
+	if len(ps) == 0 {
+		panic("candle patterns: at least one pattern is required")
+	}
+	if cs == nil {
+		cs = DefaultCandleSettings()
+	}
+
+	s := &cdlStudy{
+		pats:     append([]CandlePattern(nil), ps...),
+		lookback: make([]int, len(ps)),
+		state:    make([]cdlState, len(ps)),
+	}
+	s.ctx.cs, s.ctx.idx = cs, -1
+
+	size := 0
+	for i, p := range ps {
+		if p >= numCandlePatterns {
+			panic("candle patterns: invalid pattern")
+		}
+		if s.lookback[i] = p.Lookback(cs); s.lookback[i] > size {
+			size = s.lookback[i]
+		}
+	}
+	s.ctx.bars = make([]bar, size+1)
+	return s
+}
+
+// CandlePatternStudy alias for CandlePatterns(cs, p)
+func CandlePatternStudy(p CandlePattern, cs *CandleSettings) CandleStudy {
+	return CandlePatterns(cs, p)
+}
+
+var _ CandleStudy = (*cdlStudy)(nil)
+
+type cdlStudy struct {
+	ctx      cdlCtx
+	pats     []CandlePattern
+	lookback []int
+	state    []cdlState
+}
+
+func (s *cdlStudy) Update(vs ...Decimal) Decimal {
+	for _, v := range s.UpdateAll(vs...) {
+		if v != 0 {
+			return v
+		}
+	}
+	return 0
+}
+
+func (s *cdlStudy) UpdateAll(vs ...Decimal) []Decimal { return s.update(barFromValues(vs)) }
+func (s *cdlStudy) UpdateCandle(c *Candle) []Decimal  { return s.update(barFromCandle(c)) }
+
+func (s *cdlStudy) update(b bar) []Decimal {
+	c := &s.ctx
+	c.push(b)
+
+	out := make([]Decimal, len(s.pats))
+	for i, p := range s.pats {
+		d, lb := &cdlDefs[p], s.lookback[i]
+		// stateful patterns start looking a few candles early, same as TA-Lib
+		if c.idx < lb-d.prescan {
+			continue
+		}
+		c.pen = c.cs.Penetration
+		if c.pen == 0 {
+			c.pen = d.pen
+		}
+		if v := d.fn(c, &s.state[i]); c.idx >= lb {
+			out[i] = Decimal(v)
+		}
+	}
+	return out
+}
+
+func (s *cdlStudy) Len() int      { return 0 }
+func (s *cdlStudy) Lookback() int { return len(s.ctx.bars) - 1 }
+func (s *cdlStudy) Clone() Study  { return clone(s) }
+func (s *cdlStudy) Reset()        { *s = *CandlePatterns(s.ctx.cs, s.pats...).(*cdlStudy) }
+func (s *cdlStudy) LenAll() []int {
+	return make([]int, len(s.pats))
+}
+
+func (s *cdlStudy) ToStudy() (Study, bool)         { return s, true }
+func (s *cdlStudy) ToMulti() (MultiVarStudy, bool) { return s, true }
+
+// cdlState is used by patterns that wait for a confirmation
+type cdlState struct {
+	idx       int
+	result    int
+	high, low Decimal
+}
+
+// cdlCtx holds the last candles, the helpers mirror TA-Lib's candle macros,
+// k is how many candles ago, 0 is the current candle.
+type cdlCtx struct {
+	cs   *CandleSettings
+	bars []bar
+	pos  int
+	idx  int // index of the current candle
+	pen  Decimal
+}
+
+func (c *cdlCtx) push(b bar) {
+	c.idx++
+	c.pos = (c.pos + 1) % len(c.bars)
+	c.bars[c.pos] = b
+}
+
+func (c *cdlCtx) at(k int) *bar {
+	i := c.pos - k
+	if i < 0 {
+		i += len(c.bars)
+	}
+	return &c.bars[i]
+}
+
+func (c *cdlCtx) o(k int) Decimal { return c.at(k).open }
+func (c *cdlCtx) h(k int) Decimal { return c.at(k).high }
+func (c *cdlCtx) l(k int) Decimal { return c.at(k).low }
+func (c *cdlCtx) c(k int) Decimal { return c.at(k).close }
+
+func (c *cdlCtx) bodyTop(k int) Decimal {
+	b := c.at(k)
+	if b.close > b.open {
+		return b.close
+	}
+	return b.open
+}
+
+func (c *cdlCtx) bodyBottom(k int) Decimal {
+	b := c.at(k)
+	if b.close < b.open {
+		return b.close
+	}
+	return b.open
+}
+
+func (c *cdlCtx) rb(k int) Decimal { return (c.c(k) - c.o(k)).Abs() }
+func (c *cdlCtx) us(k int) Decimal { return c.h(k) - c.bodyTop(k) }
+func (c *cdlCtx) ls(k int) Decimal { return c.bodyBottom(k) - c.l(k) }
+
+// color returns 1 for white (close >= open) and -1 for black candles
+func (c *cdlCtx) color(k int) int {
+	if c.c(k) >= c.o(k) {
+		return 1
+	}
+	return -1
+}
+
+func (c *cdlCtx) rng(set *CandleSetting, k int) Decimal {
+	switch set.Range {
+	case RangeRealBody:
+		return c.rb(k)
+	case RangeHighLow:
+		return c.h(k) - c.l(k)
+	default:
+		return c.us(k) + c.ls(k)
+	}
+}
+
+// avg is TA_CANDLEAVERAGE, the average is over the candles before k
+func (c *cdlCtx) avg(s cdlSet, k int) Decimal {
+	set := c.cs.get(s)
+	var v Decimal
+	if set.AvgPeriod > 0 {
+		for i := 1; i <= set.AvgPeriod; i++ {
+			v += c.rng(set, k+i)
+		}
+		v /= Decimal(set.AvgPeriod)
+	} else {
+		v = c.rng(set, k)
+	}
+	v *= set.Factor
+	if set.Range == RangeShadows {
+		v /= 2
+	}
+	return v
+}
+
+// rbGapUp returns true if the real body of a (the later candle) gaps up from b's
+func (c *cdlCtx) rbGapUp(a, b int) bool   { return c.bodyBottom(a) > c.bodyTop(b) }
+func (c *cdlCtx) rbGapDown(a, b int) bool { return c.bodyTop(a) < c.bodyBottom(b) }
+func (c *cdlCtx) gapUp(a, b int) bool     { return c.l(a) > c.h(b) }
+func (c *cdlCtx) gapDown(a, b int) bool   { return c.h(a) < c.l(b) }
+
+type cdlDef struct {
+	name    string
+	sets    []cdlSet
+	extra   int
+	prescan int
+	pen     Decimal
+	fn      func(c *cdlCtx, st *cdlState) int
+}
+
+func cdlIf(ok bool, v int) int {
+	if ok {
+		return v
+	}
+	return 0
+}
@@ -0,0 +1,973 @@
+package ta
+
+// cdlDefs are ports of TA-Lib's CDL* functions, the comments and the order of the checks follow the original code.
+var cdlDefs = [numCandlePatterns]cdlDef{
+	CDL2Crows: {
+		name: "CDL2CROWS", sets: []cdlSet{setBodyLong}, extra: 2,
+		fn: func(c *cdlCtx, _ *cdlState) int {
+			return cdlIf(c.color(2) == 1 && // 1st: white
+				c.rb(2) > c.avg(setBodyLong, 2) && //      long
+				c.color(1) == -1 && // 2nd: black
+				c.rbGapUp(1, 2) && //      gapping up
+				c.color(0) == -1 && // 3rd: black
+				c.o(0) < c.o(1) && c.o(0) > c.c(1) && //      opening within 2nd rb
+				c.c(0) > c.o(2) && c.c(0) < c.c(2), //      closing within 1st rb
+				-100)
+		},
+	},
+
+	CDL3BlackCrows: {
+		name: "CDL3BLACKCROWS", sets: []cdlSet{setShadowVeryShort}, extra: 3,
+		fn: func(c *cdlCtx, _ *cdlState) int {
+			return cdlIf(c.color(3) == 1 && // white
+				c.color(2) == -1 && // 1st black
+				c.ls(2) < c.avg(setShadowVeryShort, 2) && // very short lower shadow
+				c.color(1) == -1 && // 2nd black
+				c.ls(1) < c.avg(setShadowVeryShort, 1) && // very short lower shadow
+				c.color(0) == -1 && // 3rd black
+				c.ls(0) < c.avg(setShadowVeryShort, 0) && // very short lower shadow
+				c.o(1) < c.o(2) && c.o(1) > c.c(2) && // 2nd black opens within 1st black's rb
+				c.o(0) < c.o(1) && c.o(0) > c.c(1) && // 3rd black opens within 2nd black's rb
+				c.h(3) > c.c(2) && // 1st black closes under prior candle's high
+				c.c(2) > c.c(1) && // three declining
+				c.c(1) > c.c(0), // three declining
+				-100)
+		},
+	},
+
+	CDL3Inside: {
+		name: "CDL3INSIDE", sets: []cdlSet{setBodyShort, setBodyLong}, extra: 2,
+		fn: func(c *cdlCtx, _ *cdlState) int {
+			return cdlIf(c.rb(2) > c.avg(setBodyLong, 2) && // 1st: long
+				c.rb(1) <= c.avg(setBodyShort, 1) && // 2nd: short
+				c.bodyTop(1) < c.bodyTop(2) && //      engulfed by 1st
+				c.bodyBottom(1) > c.bodyBottom(2) &&
+				((c.color(2) == 1 && c.color(0) == -1 && c.c(0) < c.o(2)) || // 3rd: opposite to 1st
+					(c.color(2) == -1 && c.color(0) == 1 && c.c(0) > c.o(2))), //      and closing out
+				-c.color(2)*100)
+		},
+	},
+
+	CDL3LineStrike: {
+		name: "CDL3LINESTRIKE", sets: []cdlSet{setNear}, extra: 3,
+		fn: func(c *cdlCtx, _ *cdlState) int {
+			return cdlIf(c.color(3) == c.color(2) && // three with same color
+				c.color(2) == c.color(1) &&
+				c.color(0) == -c.color(1) && // 4th opposite color
+				// 2nd opens within/near 1st rb
+				c.o(2) >= c.bodyBottom(3)-c.avg(setNear, 3) &&
+				c.o(2) <= c.bodyTop(3)+c.avg(setNear, 3) &&
+				// 3rd opens within/near 2nd rb
+				c.o(1) >= c.bodyBottom(2)-c.avg(setNear, 2) &&
+				c.o(1) <= c.bodyTop(2)+c.avg(setNear, 2) &&
+				(( // if three white
+				c.color(1) == 1 &&
+					c.c(1) > c.c(2) && c.c(2) > c.c(3) && // consecutive higher closes
+					c.o(0) > c.c(1) && // 4th opens above prior close
+					c.c(0) < c.o(3)) || // 4th closes below 1st open
+					( // if three black
+					c.color(1) == -1 &&
+						c.c(1) < c.c(2) && c.c(2) < c.c(3) && // consecutive lower closes
+						c.o(0) < c.c(1) && // 4th opens below prior close
+						c.c(0) > c.o(3))), // 4th closes above 1st open
+				c.color(1)*100)
+		},
+	},
+
+	CDL3Outside: {
+		name: "CDL3OUTSIDE", extra: 3,
+		fn: func(c *cdlCtx, _ *cdlState) int {
+			return cdlIf((c.color(1) == 1 && c.color(2) == -1 && // white engulfs black
+				c.c(1) > c.o(2) && c.o(1) < c.c(2) &&
+				c.c(0) > c.c(1)) || // third candle higher
+				(c.color(1) == -1 && c.color(2) == 1 && // black engulfs white
+					c.o(1) > c.c(2) && c.c(1) < c.o(2) &&
+					c.c(0) < c.c(1)), // third candle lower
+				c.color(1)*100)
+		},
+	},
+
+	CDL3StarsInSouth: {
+		name: "CDL3STARSINSOUTH", sets: []cdlSet{setShadowVeryShort, setShadowLong, setBodyLong, setBodyShort}, extra: 2,
+		fn: func(c *cdlCtx, _ *cdlState) int {
+			return cdlIf(c.color(2) == -1 && // 1st black
+				c.color(1) == -1 && // 2nd black
+				c.color(0) == -1 && // 3rd black
+				// 1st: long body, long lower shadow
+				c.rb(2) > c.avg(setBodyLong, 2) &&
+				c.ls(2) > c.avg(setShadowLong, 2) &&
+				// 2nd: smaller candle
+				c.rb(1) < c.rb(2) &&
+				// that opens higher but within 1st range
+				c.o(1) > c.c(2) && c.o(1) <= c.h(2) &&
+				// and trades lower than 1st close
+				c.l(1) < c.c(2) &&
+				// but not lower than 1st low
+				c.l(1) >= c.l(2) &&
+				// and has a lower shadow
+				c.ls(1) > c.avg(setShadowVeryShort, 1) &&
+				// 3rd: small marubozu
+				c.rb(0) < c.avg(setBodyShort, 0) &&
+				c.ls(0) < c.avg(setShadowVeryShort, 0) &&
+				c.us(0) < c.avg(setShadowVeryShort, 0) &&
+				// engulfed by prior candle's range
+				c.l(0) > c.l(1) && c.h(0) < c.h(1),
+				100)
+		},
+	},
+
+	CDL3WhiteSoldiers: {
+		name: "CDL3WHITESOLDIERS", sets: []cdlSet{setShadowVeryShort, setBodyShort, setFar, setNear}, extra: 2,
+		fn: func(c *cdlCtx, _ *cdlState) int {
+			return cdlIf(c.color(2) == 1 && // 1st white
+				c.us(2) < c.avg(setShadowVeryShort, 2) && //  very short upper shadow
+				c.color(1) == 1 && // 2nd white
+				c.us(1) < c.avg(setShadowVeryShort, 1) && //  very short upper shadow
+				c.color(0) == 1 && // 3rd white
+				c.us(0) < c.avg(setShadowVeryShort, 0) && //  very short upper shadow
+				c.c(0) > c.c(1) && c.c(1) > c.c(2) && // consecutive higher closes
+				c.o(1) > c.o(2) && // 2nd opens within/near 1st real body
+				c.o(1) <= c.c(2)+c.avg(setNear, 2) &&
+				c.o(0) > c.o(1) && // 3rd opens within/near 2nd real body
+				c.o(0) <= c.c(1)+c.avg(setNear, 1) &&
+				c.rb(1) > c.rb(2)-c.avg(setFar, 2) && // 2nd not far shorter than 1st
+				c.rb(0) > c.rb(1)-c.avg(setFar, 1) && // 3rd not far shorter than 2nd
+				c.rb(0) > c.avg(setBodyShort, 0), // not short real body
+				100)
+		},
+	},
+
+	CDLAbandonedBaby: {
+		name: "CDLABANDONEDBABY", sets: []cdlSet{setBodyDoji, setBodyLong, setBodyShort}, extra: 2, pen: 0.3,
+		fn: func(c *cdlCtx, _ *cdlState) int {
+			return cdlIf(c.rb(2) > c.avg(setBodyLong, 2) && // 1st: long
+				c.rb(1) <= c.avg(setBodyDoji, 1) && // 2nd: doji
+				c.rb(0) > c.avg(setBodyShort, 0) && // 3rd: longer than short
+				((c.color(2) == 1 && // 1st white
+					c.color(0) == -1 && // 3rd black
+					c.c(0) < c.c(2)-c.rb(2)*c.pen && // 3rd closes well within 1st rb
+					c.gapUp(1, 2) && // upside gap between 1st and 2nd
+					c.gapDown(0, 1)) || // downside gap between 2nd and 3rd
+					(c.color(2) == -1 && // 1st black
+						c.color(0) == 1 && // 3rd white
+						c.c(0) > c.c(2)+c.rb(2)*c.pen && // 3rd closes well within 1st rb
+						c.gapDown(1, 2) && // downside gap between 1st and 2nd
+						c.gapUp(0, 1))), // upside gap between 2nd and 3rd
+				c.color(0)*100)
+		},
+	},
+
+	CDLAdvanceBlock: {
+		name: "CDLADVANCEBLOCK", sets: []cdlSet{setShadowLong, setShadowShort, setFar, setNear, setBodyLong}, extra: 2,
+		fn: func(c *cdlCtx, _ *cdlState) int {
+			return cdlIf(c.color(2) == 1 && // 1st white
+				c.color(1) == 1 && // 2nd white
+				c.color(0) == 1 && // 3rd white
+				c.c(0) > c.c(1) && c.c(1) > c.c(2) && // consecutive higher closes
+				c.o(1) > c.o(2) && // 2nd opens within/near 1st real body
+				c.o(1) <= c.c(2)+c.avg(setNear, 2) &&
+				c.o(0) > c.o(1) && // 3rd opens within/near 2nd real body
+				c.o(0) <= c.c(1)+c.avg(setNear, 1) &&
+				c.rb(2) > c.avg(setBodyLong, 2) && // 1st: long real body
+				c.us(2) < c.avg(setShadowShort, 2) && // 1st: short upper shadow
+				(
+				// ( 2 far smaller than 1 && 3 not longer than 2 )
+				// advance blocked with the 2nd, 3rd must not carry on the advance
+				(c.rb(1) < c.rb(2)-c.avg(setFar, 2) &&
+					c.rb(0) < c.rb(1)+c.avg(setNear, 1)) ||
+					// 3 far smaller than 2
+					// advance blocked with the 3rd
+					(c.rb(0) < c.rb(1)-c.avg(setFar, 1)) ||
+					// ( 3 smaller than 2 && 2 smaller than 1 && (3 or 2 not short upper shadow) )
+					// advance blocked with progressively smaller real bodies and some upper shadows
+					(c.rb(0) < c.rb(1) &&
+						c.rb(1) < c.rb(2) &&
+						(c.us(0) > c.avg(setShadowShort, 0) ||
+							c.us(1) > c.avg(setShadowShort, 1))) ||
+					// ( 3 smaller than 2 && 3 long upper shadow )
+					// advance blocked with 3rd candle's long upper shadow and smaller body
+					(c.rb(0) < c.rb(1) &&
+						c.us(0) > c.avg(setShadowLong, 0))),
+				-100)
+		},
+	},
+
+	CDLBeltHold: {
+		name: "CDLBELTHOLD", sets: []cdlSet{setBodyLong, setShadowVeryShort},
+		fn: func(c *cdlCtx, _ *cdlState) int {
+			return cdlIf(c.rb(0) > c.avg(setBodyLong, 0) && // long body
+				((c.color(0) == 1 && // white body and very short lower shadow
+					c.ls(0) < c.avg(setShadowVeryShort, 0)) ||
+					(c.color(0) == -1 && // black body and very short upper shadow
+						c.us(0) < c.avg(setShadowVeryShort, 0))),
+				c.color(0)*100)
+		},
+	},
+
+	CDLBreakaway: {
+		name: "CDLBREAKAWAY", sets: []cdlSet{setBodyLong}, extra: 4,
+		fn: func(c *cdlCtx, _ *cdlState) int {
+			return cdlIf(c.rb(4) > c.avg(setBodyLong, 4) && // 1st long
+				c.color(4) == c.color(3) && // 1st, 2nd, 4th same color, 5th opposite
+				c.color(3) == c.color(1) &&
+				c.color(1) == -c.color(0) &&
+				((c.color(4) == -1 && // when 1st is black:
+					c.rbGapDown(3, 4) && // 2nd gaps down
+					c.h(2) < c.h(3) && c.l(2) < c.l(3) && // 3rd has lower high and low than 2nd
+					c.h(1) < c.h(2) && c.l(1) < c.l(2) && // 4th has lower high and low than 3rd
+					c.c(0) > c.o(3) && c.c(0) < c.c(4)) || // 5th closes inside the gap
+					(c.color(4) == 1 && // when 1st is white:
+						c.rbGapUp(3, 4) && // 2nd gaps up
+						c.h(2) > c.h(3) && c.l(2) > c.l(3) && // 3rd has higher high and low than 2nd
+						c.h(1) > c.h(2) && c.l(1) > c.l(2) && // 4th has higher high and low than 3rd
+						c.c(0) < c.o(3) && c.c(0) > c.c(4))), // 5th closes inside the gap
+				c.color(0)*100)
+		},
+	},
+
+	CDLClosingMarubozu: {
+		name: "CDLCLOSINGMARUBOZU", sets: []cdlSet{setBodyLong, setShadowVeryShort},
+		fn: func(c *cdlCtx, _ *cdlState) int {
+			return cdlIf(c.rb(0) > c.avg(setBodyLong, 0) && // long body
+				((c.color(0) == 1 && // white body and very short upper shadow
+					c.us(0) < c.avg(setShadowVeryShort, 0)) ||
+					(c.color(0) == -1 && // black body and very short lower shadow
+						c.ls(0) < c.avg(setShadowVeryShort, 0))),
+				c.color(0)*100)
+		},
+	},
+
+	CDLConcealBabySwall: {
+		name: "CDLCONCEALBABYSWALL", sets: []cdlSet{setShadowVeryShort}, extra: 3,
+		fn: func(c *cdlCtx, _ *cdlState) int {
+			return cdlIf(c.color(3) == -1 && // 1st black
+				c.color(2) == -1 && // 2nd black
+				c.color(1) == -1 && // 3rd black
+				c.color(0) == -1 && // 4th black
+				// 1st: marubozu
+				c.ls(3) < c.avg(setShadowVeryShort, 3) &&
+				c.us(3) < c.avg(setShadowVeryShort, 3) &&
+				// 2nd: marubozu
+				c.ls(2) < c.avg(setShadowVeryShort, 2) &&
+				c.us(2) < c.avg(setShadowVeryShort, 2) &&
+				c.rbGapDown(1, 2) && // 3rd: opens gapping down
+				//      and HAS an upper shadow
+				c.us(1) > c.avg(setShadowVeryShort, 1) &&
+				c.h(1) > c.c(2) && //      that extends into the prior body
+				c.h(0) > c.h(1) && c.l(0) < c.l(1), // 4th: engulfs the 3rd including the shadows
+				100)
+		},
+	},
+
+	CDLCounterAttack: {
+		name: "CDLCOUNTERATTACK", sets: []cdlSet{setEqual, setBodyLong}, extra: 1,
+		fn: func(c *cdlCtx, _ *cdlState) int {
+			return cdlIf(c.color(1) == -c.color(0) && // opposite candles
+				c.rb(1) > c.avg(setBodyLong, 1) && // 1st long
+				c.rb(0) > c.avg(setBodyLong, 0) && // 2nd long
+				c.c(0) <= c.c(1)+c.avg(setEqual, 1) && // equal closes
+				c.c(0) >= c.c(1)-c.avg(setEqual, 1),
+				c.color(0)*100)
+		},
+	},
+
+	CDLDarkCloudCover: {
+		name: "CDLDARKCLOUDCOVER", sets: []cdlSet{setBodyLong}, extra: 1, pen: 0.5,
+		fn: func(c *cdlCtx, _ *cdlState) int {
+			return cdlIf(c.color(1) == 1 && // 1st: white
+				c.rb(1) > c.avg(setBodyLong, 1) && //      long
+				c.color(0) == -1 && // 2nd: black
+				c.o(0) > c.h(1) && //      open above prior high
+				c.c(0) > c.o(1) && //      close within prior body
+				c.c(0) < c.c(1)-c.rb(1)*c.pen,
+				-100)
+		},
+	},
+
+	CDLDoji: {
+		name: "CDLDOJI", sets: []cdlSet{setBodyDoji},
+		fn: func(c *cdlCtx, _ *cdlState) int {
+			return cdlIf(c.rb(0) <= c.avg(setBodyDoji, 0), 100)
+		},
+	},
+
+	CDLDojiStar: {
+		name: "CDLDOJISTAR", sets: []cdlSet{setBodyDoji, setBodyLong}, extra: 1,
+		fn: func(c *cdlCtx, _ *cdlState) int {
+			return cdlIf(c.rb(1) > c.avg(setBodyLong, 1) && // 1st: long real body
+				c.rb(0) <= c.avg(setBodyDoji, 0) && // 2nd: doji
+				((c.color(1) == 1 && c.rbGapUp(0, 1)) || //      that gaps up if 1st is white
+					(c.color(1) == -1 && c.rbGapDown(0, 1))), //      or down if 1st is black
+				-c.color(1)*100)
+		},
+	},
+
+	CDLDragonflyDoji: {
+		name: "CDLDRAGONFLYDOJI", sets: []cdlSet{setBodyDoji, setShadowVeryShort},
+		fn: func(c *cdlCtx, _ *cdlState) int {
+			return cdlIf(c.rb(0) <= c.avg(setBodyDoji, 0) &&
+				c.us(0) < c.avg(setShadowVeryShort, 0) &&
+				c.ls(0) > c.avg(setShadowVeryShort, 0),
+				100)
+		},
+	},
+
+	CDLEngulfing: {
+		name: "CDLENGULFING", extra: 2,
+		fn: func(c *cdlCtx, _ *cdlState) int {
+			if (c.color(0) == 1 && c.color(1) == -1 && // white engulfs black
+				((c.c(0) >= c.o(1) && c.o(0) < c.c(1)) ||
+					(c.c(0) > c.o(1) && c.o(0) <= c.c(1)))) ||
+				(c.color(0) == -1 && c.color(1) == 1 && // black engulfs white
+					((c.o(0) >= c.c(1) && c.c(0) < c.o(1)) ||
+						(c.o(0) > c.c(1) && c.c(0) <= c.o(1)))) {
+				if c.o(0) != c.c(1) && c.c(0) != c.o(1) {
+					return c.color(0) * 100
+				}
+				return c.color(0) * 80
+			}
+			return 0
+		},
+	},
+
+	CDLEveningDojiStar: {
+		name: "CDLEVENINGDOJISTAR", sets: []cdlSet{setBodyDoji, setBodyLong, setBodyShort}, extra: 2, pen: 0.3,
+		fn: func(c *cdlCtx, _ *cdlState) int {
+			return cdlIf(c.rb(2) > c.avg(setBodyLong, 2) && // 1st: long
+				c.color(2) == 1 && //      white
+				c.rb(1) <= c.avg(setBodyDoji, 1) && // 2nd: doji
+				c.rbGapUp(1, 2) && //      gapping up
+				c.rb(0) > c.avg(setBodyShort, 0) && // 3rd: longer than short
+				c.color(0) == -1 && //      black real body
+				c.c(0) < c.c(2)-c.rb(2)*c.pen, //      closing well within 1st rb
+				-100)
+		},
+	},
+
+	CDLEveningStar: {
+		name: "CDLEVENINGSTAR", sets: []cdlSet{setBodyShort, setBodyLong}, extra: 2, pen: 0.3,
+		fn: func(c *cdlCtx, _ *cdlState) int {
+			return cdlIf(c.rb(2) > c.avg(setBodyLong, 2) && // 1st: long
+				c.color(2) == 1 && //      white
+				c.rb(1) <= c.avg(setBodyShort, 1) && // 2nd: short
+				c.rbGapUp(1, 2) && //      gapping up
+				c.rb(0) > c.avg(setBodyShort, 0) && // 3rd: longer than short
+				c.color(0) == -1 && //      black real body
+				c.c(0) < c.c(2)-c.rb(2)*c.pen, //      closing well within 1st rb
+				-100)
+		},
+	},
+
+	CDLGapSideSideWhite: {
+		name: "CDLGAPSIDESIDEWHITE", sets: []cdlSet{setNear, setEqual}, extra: 2,
+		fn: func(c *cdlCtx, _ *cdlState) int {
+			up := c.rbGapUp(1, 2) && c.rbGapUp(0, 2)
+			return cdlIf((up || // upside or downside gap between the 1st candle and both the next 2 candles
+				(c.rbGapDown(1, 2) && c.rbGapDown(0, 2))) &&
+				c.color(1) == 1 && // 2nd: white
+				c.color(0) == 1 && // 3rd: white
+				c.rb(0) >= c.rb(1)-c.avg(setNear, 1) && // same size 2 and 3
+				c.rb(0) <= c.rb(1)+c.avg(setNear, 1) &&
+				c.o(0) >= c.o(1)-c.avg(setEqual, 1) && // same open 2 and 3
+				c.o(0) <= c.o(1)+c.avg(setEqual, 1),
+				cdlSign(c.rbGapUp(1, 2)))
+		},
+	},
+
+	CDLGravestoneDoji: {
+		name: "CDLGRAVESTONEDOJI", sets: []cdlSet{setBodyDoji, setShadowVeryShort},
+		fn: func(c *cdlCtx, _ *cdlState) int {
+			return cdlIf(c.rb(0) <= c.avg(setBodyDoji, 0) &&
+				c.ls(0) < c.avg(setShadowVeryShort, 0) &&
+				c.us(0) > c.avg(setShadowVeryShort, 0),
+				100)
+		},
+	},
+
+	CDLHammer: {
+		name: "CDLHAMMER", sets: []cdlSet{setBodyShort, setShadowLong, setShadowVeryShort, setNear}, extra: 1,
+		fn: func(c *cdlCtx, _ *cdlState) int {
+			return cdlIf(c.rb(0) < c.avg(setBodyShort, 0) && // small rb
+				c.ls(0) > c.avg(setShadowLong, 0) && // long lower shadow
+				c.us(0) < c.avg(setShadowVeryShort, 0) && // very short upper shadow
+				c.bodyBottom(0) <= c.l(1)+c.avg(setNear, 1), // rb near the prior candle's lows
+				100)
+		},
+	},
+
+	CDLHangingMan: {
+		name: "CDLHANGINGMAN", sets: []cdlSet{setBodyShort, setShadowLong, setShadowVeryShort, setNear}, extra: 1,
+		fn: func(c *cdlCtx, _ *cdlState) int {
+			return cdlIf(c.rb(0) < c.avg(setBodyShort, 0) && // small rb
+				c.ls(0) > c.avg(setShadowLong, 0) && // long lower shadow
+				c.us(0) < c.avg(setShadowVeryShort, 0) && // very short upper shadow
+				c.bodyBottom(0) >= c.h(1)-c.avg(setNear, 1), // rb near the prior candle's highs
+				-100)
+		},
+	},
+
+	CDLHarami: {
+		name: "CDLHARAMI", sets: []cdlSet{setBodyShort, setBodyLong}, extra: 1,
+		fn: func(c *cdlCtx, _ *cdlState) int {
+			return cdlHarami(c, setBodyShort)
+		},
+	},
+
+	CDLHaramiCross: {
+		name: "CDLHARAMICROSS", sets: []cdlSet{setBodyDoji, setBodyLong}, extra: 1,
+		fn: func(c *cdlCtx, _ *cdlState) int {
+			return cdlHarami(c, setBodyDoji)
+		},
+	},
+
+	CDLHighWave: {
+		name: "CDLHIGHWAVE", sets: []cdlSet{setBodyShort, setShadowVeryLong},
+		fn: func(c *cdlCtx, _ *cdlState) int {
+			return cdlIf(c.rb(0) < c.avg(setBodyShort, 0) &&
+				c.us(0) > c.avg(setShadowVeryLong, 0) &&
+				c.ls(0) > c.avg(setShadowVeryLong, 0),
+				c.color(0)*100)
+		},
+	},
+
+	CDLHikkake: {
+		name: "CDLHIKKAKE", extra: 5, prescan: 3,
+		fn: func(c *cdlCtx, st *cdlState) int {
+			found := c.h(1) < c.h(2) && c.l(1) > c.l(2) && // 1st + 2nd: lower high and higher low
+				((c.h(0) < c.h(1) && c.l(0) < c.l(1)) || // (bull) 3rd: lower high and lower low
+					(c.h(0) > c.h(1) && c.l(0) > c.l(1))) // (bear) 3rd: higher high and higher low
+			return cdlHikkake(c, st, found)
+		},
+	},
+
+	CDLHikkakeMod: {
+		name: "CDLHIKKAKEMOD", sets: []cdlSet{setNear}, extra: 5, prescan: 3,
+		fn: func(c *cdlCtx, st *cdlState) int {
+			found := c.h(2) < c.h(3) && c.l(2) > c.l(3) && // 2nd: lower high and higher low than 1st
+				c.h(1) < c.h(2) && c.l(1) > c.l(2) && // 3rd: lower high and higher low than 2nd
+				(( // (bull) 4th: lower high and lower low
+				c.h(0) < c.h(1) && c.l(0) < c.l(1) &&
+					c.c(2) <= c.l(2)+c.avg(setNear, 2)) || // (bull) 2nd: close near the low
+					( // (bear) 4th: higher high and higher low
+					c.h(0) > c.h(1) && c.l(0) > c.l(1) &&
+						c.c(2) >= c.h(2)-c.avg(setNear, 2))) // (bear) 2nd: close near the top
+			return cdlHikkake(c, st, found)
+		},
+	},
+
+	CDLHomingPigeon: {
+		name: "CDLHOMINGPIGEON", sets: []cdlSet{setBodyShort, setBodyLong}, extra: 1,
+		fn: func(c *cdlCtx, _ *cdlState) int {
+			return cdlIf(c.color(1) == -1 && // 1st black
+				c.color(0) == -1 && // 2nd black
+				c.rb(1) > c.avg(setBodyLong, 1) && // 1st long
+				c.rb(0) <= c.avg(setBodyShort, 0) && // 2nd short
+				c.o(0) < c.o(1) && // 2nd engulfed by 1st
+				c.c(0) > c.c(1),
+				100)
+		},
+	},
+
+	CDLIdentical3Crows: {
+		name: "CDLIDENTICAL3CROWS", sets: []cdlSet{setShadowVeryShort, setEqual}, extra: 2,
+		fn: func(c *cdlCtx, _ *cdlState) int {
+			return cdlIf(c.color(2) == -1 && // 1st black
+				c.ls(2) < c.avg(setShadowVeryShort, 2) && // very short lower shadow
+				c.color(1) == -1 && // 2nd black
+				c.ls(1) < c.avg(setShadowVeryShort, 1) && // very short lower shadow
+				c.color(0) == -1 && // 3rd black
+				c.ls(0) < c.avg(setShadowVeryShort, 0) && // very short lower shadow
+				c.c(2) > c.c(1) && // three declining
+				c.c(1) > c.c(0) &&
+				c.o(1) <= c.c(2)+c.avg(setEqual, 2) && // 2nd black opens very close to 1st close
+				c.o(1) >= c.c(2)-c.avg(setEqual, 2) &&
+				c.o(0) <= c.c(1)+c.avg(setEqual, 1) && // 3rd black opens very close to 2nd close
+				c.o(0) >= c.c(1)-c.avg(setEqual, 1),
+				-100)
+		},
+	},
+
+	CDLInNeck: {
+		name: "CDLINNECK", sets: []cdlSet{setEqual, setBodyLong}, extra: 1,
+		fn: func(c *cdlCtx, _ *cdlState) int {
+			return cdlIf(c.color(1) == -1 && // 1st: black
+				c.rb(1) > c.avg(setBodyLong, 1) && //  long
+				c.color(0) == 1 && // 2nd: white
+				c.o(0) < c.l(1) && //  open below prior low
+				c.c(0) <= c.c(1)+c.avg(setEqual, 1) && //  close slightly into prior body
+				c.c(0) >= c.c(1),
+				-100)
+		},
+	},
+
+	CDLInvertedHammer: {
+		name: "CDLINVERTEDHAMMER", sets: []cdlSet{setBodyShort, setShadowLong, setShadowVeryShort}, extra: 1,
+		fn: func(c *cdlCtx, _ *cdlState) int {
+			return cdlIf(c.rb(0) < c.avg(setBodyShort, 0) && // small rb
+				c.us(0) > c.avg(setShadowLong, 0) && // long upper shadow
+				c.ls(0) < c.avg(setShadowVeryShort, 0) && // very short lower shadow
+				c.rbGapDown(0, 1), // gap down
+				100)
+		},
+	},
+
+	CDLKicking: {
+		name: "CDLKICKING", sets: []cdlSet{setShadowVeryShort, setBodyLong}, extra: 1,
+		fn: func(c *cdlCtx, _ *cdlState) int {
+			return cdlIf(cdlKicking(c), c.color(0)*100)
+		},
+	},
+
+	CDLKickingByLength: {
+		name: "CDLKICKINGBYLENGTH", sets: []cdlSet{setShadowVeryShort, setBodyLong}, extra: 1,
+		fn: func(c *cdlCtx, _ *cdlState) int {
+			if !cdlKicking(c) {
+				return 0
+			}
+			if c.rb(0) > c.rb(1) {
+				return c.color(0) * 100
+			}
+			return c.color(1) * 100
+		},
+	},
+
+	CDLLadderBottom: {
+		name: "CDLLADDERBOTTOM", sets: []cdlSet{setShadowVeryShort}, extra: 4,
+		fn: func(c *cdlCtx, _ *cdlState) int {
+			return cdlIf(c.color(4) == -1 && c.color(3) == -1 && c.color(2) == -1 && // 3 black candlesticks
+				c.o(4) > c.o(3) && c.o(3) > c.o(2) && // with consecutively lower opens
+				c.c(4) > c.c(3) && c.c(3) > c.c(2) && // and closes
+				c.color(1) == -1 && // 4th: black with an upper shadow
+				c.us(1) > c.avg(setShadowVeryShort, 1) &&
+				c.color(0) == 1 && // 5th: white
+				c.o(0) > c.o(1) && // that opens above prior candle's body
+				c.c(0) > c.h(1), // and closes above prior candle's high
+				100)
+		},
+	},
+
+	CDLLongLeggedDoji: {
+		name: "CDLLONGLEGGEDDOJI", sets: []cdlSet{setBodyDoji, setShadowLong},
+		fn: func(c *cdlCtx, _ *cdlState) int {
+			return cdlIf(c.rb(0) <= c.avg(setBodyDoji, 0) &&
+				(c.ls(0) > c.avg(setShadowLong, 0) ||
+					c.us(0) > c.avg(setShadowLong, 0)),
+				100)
+		},
+	},
+
+	CDLLongLine: {
+		name: "CDLLONGLINE", sets: []cdlSet{setBodyLong, setShadowShort},
+		fn: func(c *cdlCtx, _ *cdlState) int {
+			return cdlIf(c.rb(0) > c.avg(setBodyLong, 0) &&
+				c.us(0) < c.avg(setShadowShort, 0) &&
+				c.ls(0) < c.avg(setShadowShort, 0),
+				c.color(0)*100)
+		},
+	},
+
+	CDLMarubozu: {
+		name: "CDLMARUBOZU", sets: []cdlSet{setBodyLong, setShadowVeryShort},
+		fn: func(c *cdlCtx, _ *cdlState) int {
+			return cdlIf(c.rb(0) > c.avg(setBodyLong, 0) &&
+				c.us(0) < c.avg(setShadowVeryShort, 0) &&
+				c.ls(0) < c.avg(setShadowVeryShort, 0),
+				c.color(0)*100)
+		},
+	},
+
+	CDLMatchingLow: {
+		name: "CDLMATCHINGLOW", sets: []cdlSet{setEqual}, extra: 1,
+		fn: func(c *cdlCtx, _ *cdlState) int {
+			return cdlIf(c.color(1) == -1 && // first black
+				c.color(0) == -1 && // second black
+				c.c(0) <= c.c(1)+c.avg(setEqual, 1) && // 1st and 2nd same close
+				c.c(0) >= c.c(1)-c.avg(setEqual, 1),
+				100)
+		},
+	},
+
+	CDLMatHold: {
+		name: "CDLMATHOLD", sets: []cdlSet{setBodyShort, setBodyLong}, extra: 4, pen: 0.5,
+		fn: func(c *cdlCtx, _ *cdlState) int {
+			return cdlIf(
+				// 1st long, then 3 small
+				c.rb(4) > c.avg(setBodyLong, 4) &&
+					c.rb(3) < c.avg(setBodyShort, 3) &&
+					c.rb(2) < c.avg(setBodyShort, 2) &&
+					c.rb(1) < c.avg(setBodyShort, 1) &&
+					// white, black, 2 black or white, white
+					c.color(4) == 1 &&
+					c.color(3) == -1 &&
+					c.color(0) == 1 &&
+					// upside gap 1st to 2nd
+					c.rbGapUp(3, 4) &&
+					// 3rd to 4th hold within 1st: a part of the real body must be within 1st real body
+					c.bodyBottom(2) < c.c(4) &&
+					c.bodyBottom(1) < c.c(4) &&
+					// reaction days penetrate first body less than optInPenetration percent
+					c.bodyBottom(2) > c.c(4)-c.rb(4)*c.pen &&
+					c.bodyBottom(1) > c.c(4)-c.rb(4)*c.pen &&
+					// 2nd to 4th are falling
+					c.bodyTop(2) < c.o(3) &&
+					c.bodyTop(1) < c.bodyTop(2) &&
+					// 5th opens above the prior close
+					c.o(0) > c.c(1) &&
+					// 5th closes above the highest high of the reaction days
+					c.c(0) > maxDecimal(c.h(3), c.h(2), c.h(1)),
+				100)
+		},
+	},
+
+	CDLMorningDojiStar: {
+		name: "CDLMORNINGDOJISTAR", sets: []cdlSet{setBodyDoji, setBodyLong, setBodyShort}, extra: 2, pen: 0.3,
+		fn: func(c *cdlCtx, _ *cdlState) int {
+			return cdlIf(c.rb(2) > c.avg(setBodyLong, 2) && // 1st: long
+				c.color(2) == -1 && //      black
+				c.rb(1) <= c.avg(setBodyDoji, 1) && // 2nd: doji
+				c.rbGapDown(1, 2) && //      gapping down
+				c.rb(0) > c.avg(setBodyShort, 0) && // 3rd: longer than short
+				c.color(0) == 1 && //      white real body
+				c.c(0) > c.c(2)+c.rb(2)*c.pen, //      closing well within 1st rb
+				100)
+		},
+	},
+
+	CDLMorningStar: {
+		name: "CDLMORNINGSTAR", sets: []cdlSet{setBodyShort, setBodyLong}, extra: 2, pen: 0.3,
+		fn: func(c *cdlCtx, _ *cdlState) int {
+			return cdlIf(c.rb(2) > c.avg(setBodyLong, 2) && // 1st: long
+				c.color(2) == -1 && //      black
+				c.rb(1) <= c.avg(setBodyShort, 1) && // 2nd: short
+				c.rbGapDown(1, 2) && //      gapping down
+				c.rb(0) > c.avg(setBodyShort, 0) && // 3rd: longer than short
+				c.color(0) == 1 && //      white real body
+				c.c(0) > c.c(2)+c.rb(2)*c.pen, //      closing well within 1st rb
+				100)
+		},
+	},
+
+	CDLOnNeck: {
+		name: "CDLONNECK", sets: []cdlSet{setEqual, setBodyLong}, extra: 1,
+		fn: func(c *cdlCtx, _ *cdlState) int {
+			return cdlIf(c.color(1) == -1 && // 1st: black
+				c.rb(1) > c.avg(setBodyLong, 1) && //  long
+				c.color(0) == 1 && // 2nd: white
+				c.o(0) < c.l(1) && //  open below prior low
+				c.c(0) <= c.l(1)+c.avg(setEqual, 1) && //  close equal to prior low
+				c.c(0) >= c.l(1)-c.avg(setEqual, 1),
+				-100)
+		},
+	},
+
+	CDLPiercing: {
+		name: "CDLPIERCING", sets: []cdlSet{setBodyLong}, extra: 1,
+		fn: func(c *cdlCtx, _ *cdlState) int {
+			return cdlIf(c.color(1) == -1 && // 1st: black
+				c.rb(1) > c.avg(setBodyLong, 1) && //  long
+				c.color(0) == 1 && // 2nd: white
+				c.rb(0) > c.avg(setBodyLong, 0) && //  long
+				c.o(0) < c.l(1) && //  open below prior low
+				c.c(0) < c.o(1) && //  close within prior body
+				c.c(0) > c.c(1)+c.rb(1)*0.5, //    above midpoint
+				100)
+		},
+	},
+
+	CDLRickshawMan: {
+		name: "CDLRICKSHAWMAN", sets: []cdlSet{setBodyDoji, setShadowLong, setNear},
+		fn: func(c *cdlCtx, _ *cdlState) int {
+			mid := c.l(0) + (c.h(0)-c.l(0))/2
+			return cdlIf(c.rb(0) <= c.avg(setBodyDoji, 0) && // doji
+				c.ls(0) > c.avg(setShadowLong, 0) && // long shadow
+				c.us(0) > c.avg(setShadowLong, 0) && // long shadow
+				c.bodyBottom(0) <= mid+c.avg(setNear, 0) && // body near midpoint
+				c.bodyTop(0) >= mid-c.avg(setNear, 0),
+				100)
+		},
+	},
+
+	CDLRiseFall3Methods: {
+		name: "CDLRISEFALL3METHODS", sets: []cdlSet{setBodyShort, setBodyLong}, extra: 4,
+		fn: func(c *cdlCtx, _ *cdlState) int {
+			clr := Decimal(c.color(4))
+			return cdlIf(
+				// 1st long, then 3 small, 5th long
+				c.rb(4) > c.avg(setBodyLong, 4) &&
+					c.rb(3) < c.avg(setBodyShort, 3) &&
+					c.rb(2) < c.avg(setBodyShort, 2) &&
+					c.rb(1) < c.avg(setBodyShort, 1) &&
+					c.rb(0) > c.avg(setBodyLong, 0) &&
+					// white, 3 black, white  ||  black, 3 white, black
+					c.color(4) == -c.color(3) &&
+					c.color(3) == c.color(2) &&
+					c.color(2) == c.color(1) &&
+					c.color(1) == -c.color(0) &&
+					// 2nd to 4th hold within 1st: a part of the real body must be within 1st range
+					c.bodyBottom(3) < c.h(4) && c.bodyTop(3) > c.l(4) &&
+					c.bodyBottom(2) < c.h(4) && c.bodyTop(2) > c.l(4) &&
+					c.bodyBottom(1) < c.h(4) && c.bodyTop(1) > c.l(4) &&
+					// 2nd to 4th are falling (rising)
+					c.c(2)*clr < c.c(3)*clr &&
+					c.c(1)*clr < c.c(2)*clr &&
+					// 5th opens above (below) the prior close
+					c.o(0)*clr > c.c(1)*clr &&
+					// 5th closes above (below) the 1st close
+					c.c(0)*clr > c.c(4)*clr,
+				100*c.color(4))
+		},
+	},
+
+	CDLSeparatingLines: {
+		name: "CDLSEPARATINGLINES", sets: []cdlSet{setShadowVeryShort, setBodyLong, setEqual}, extra: 1,
+		fn: func(c *cdlCtx, _ *cdlState) int {
+			return cdlIf(c.color(1) == -c.color(0) && // opposite candles
+				c.o(0) <= c.o(1)+c.avg(setEqual, 1) && // same open
+				c.o(0) >= c.o(1)-c.avg(setEqual, 1) &&
+				c.rb(0) > c.avg(setBodyLong, 0) && // belt hold: long body
+				((c.color(0) == 1 && // with no lower shadow if bullish
+					c.ls(0) < c.avg(setShadowVeryShort, 0)) ||
+					(c.color(0) == -1 && // with no upper shadow if bearish
+						c.us(0) < c.avg(setShadowVeryShort, 0))),
+				c.color(0)*100)
+		},
+	},
+
+	CDLShootingStar: {
+		name: "CDLSHOOTINGSTAR", sets: []cdlSet{setBodyShort, setShadowLong, setShadowVeryShort}, extra: 1,
+		fn: func(c *cdlCtx, _ *cdlState) int {
+			return cdlIf(c.rb(0) < c.avg(setBodyShort, 0) && // small rb
+				c.us(0) > c.avg(setShadowLong, 0) && // long upper shadow
+				c.ls(0) < c.avg(setShadowVeryShort, 0) && // very short lower shadow
+				c.rbGapUp(0, 1), // gap up
+				-100)
+		},
+	},
+
+	CDLShortLine: {
+		name: "CDLSHORTLINE", sets: []cdlSet{setBodyShort, setShadowShort},
+		fn: func(c *cdlCtx, _ *cdlState) int {
+			return cdlIf(c.rb(0) < c.avg(setBodyShort, 0) &&
+				c.us(0) < c.avg(setShadowShort, 0) &&
+				c.ls(0) < c.avg(setShadowShort, 0),
+				c.color(0)*100)
+		},
+	},
+
+	CDLSpinningTop: {
+		name: "CDLSPINNINGTOP", sets: []cdlSet{setBodyShort},
+		fn: func(c *cdlCtx, _ *cdlState) int {
+			return cdlIf(c.rb(0) < c.avg(setBodyShort, 0) &&
+				c.us(0) > c.rb(0) &&
+				c.ls(0) > c.rb(0),
+				c.color(0)*100)
+		},
+	},
+
+	CDLStalledPattern: {
+		name: "CDLSTALLEDPATTERN", sets: []cdlSet{setBodyLong, setBodyShort, setShadowVeryShort, setNear}, extra: 2,
+		fn: func(c *cdlCtx, _ *cdlState) int {
+			return cdlIf(c.color(2) == 1 && // 1st white
+				c.color(1) == 1 && // 2nd white
+				c.color(0) == 1 && // 3rd white
+				c.c(0) > c.c(1) && c.c(1) > c.c(2) && // consecutive higher closes
+				c.rb(2) > c.avg(setBodyLong, 2) && // 1st: long real body
+				c.rb(1) > c.avg(setBodyLong, 1) && // 2nd: long real body
+				c.us(1) < c.avg(setShadowVeryShort, 1) && // very short upper shadow
+				c.o(1) > c.o(2) && // opens within/near 1st real body
+				c.o(1) <= c.c(2)+c.avg(setNear, 2) &&
+				c.rb(0) < c.avg(setBodyShort, 0) && // 3rd: small real body
+				c.o(0) >= c.c(1)-c.rb(0)-c.avg(setNear, 1), // rides on the shoulder of 2nd real body
+				-100)
+		},
+	},
+
+	CDLStickSandwich: {
+		name: "CDLSTICKSANDWICH", sets: []cdlSet{setEqual}, extra: 2,
+		fn: func(c *cdlCtx, _ *cdlState) int {
+			return cdlIf(c.color(2) == -1 && // first black
+				c.color(1) == 1 && // second white
+				c.color(0) == -1 && // third black
+				c.l(1) > c.c(2) && // 2nd low > prior close
+				c.c(0) <= c.c(2)+c.avg(setEqual, 2) && // 1st and 3rd same close
+				c.c(0) >= c.c(2)-c.avg(setEqual, 2),
+				100)
+		},
+	},
+
+	CDLTakuri: {
+		name: "CDLTAKURI", sets: []cdlSet{setBodyDoji, setShadowVeryShort, setShadowVeryLong},
+		fn: func(c *cdlCtx, _ *cdlState) int {
+			return cdlIf(c.rb(0) <= c.avg(setBodyDoji, 0) &&
+				c.us(0) < c.avg(setShadowVeryShort, 0) &&
+				c.ls(0) > c.avg(setShadowVeryLong, 0),
+				100)
+		},
+	},
+
+	CDLTasukiGap: {
+		name: "CDLTASUKIGAP", sets: []cdlSet{setNear}, extra: 2,
+		fn: func(c *cdlCtx, _ *cdlState) int {
+			return cdlIf((c.rbGapUp(1, 2) && // upside gap
+				c.color(1) == 1 && // 1st: white
+				c.color(0) == -1 && // 2nd: black
+				c.o(0) < c.c(1) && c.o(0) > c.o(1) && //      that opens within the white rb
+				c.c(0) < c.o(1) && //      and closes under the white rb
+				c.c(0) > c.bodyTop(2) && //      inside the gap
+				(c.rb(1)-c.rb(0)).Abs() < c.avg(setNear, 1)) || // size of 2 rb near the same
+				(c.rbGapDown(1, 2) && // downside gap
+					c.color(1) == -1 && // 1st: black
+					c.color(0) == 1 && // 2nd: white
+					c.o(0) < c.o(1) && c.o(0) > c.c(1) && //      that opens within the black rb
+					c.c(0) > c.o(1) && //      and closes above the black rb
+					c.c(0) < c.bodyBottom(2) && //      inside the gap
+					(c.rb(1)-c.rb(0)).Abs() < c.avg(setNear, 1)), // size of 2 rb near the same
+				c.color(1)*100)
+		},
+	},
+
+	CDLThrusting: {
+		name: "CDLTHRUSTING", sets: []cdlSet{setEqual, setBodyLong}, extra: 1,
+		fn: func(c *cdlCtx, _ *cdlState) int {
+			return cdlIf(c.color(1) == -1 && // 1st: black
+				c.rb(1) > c.avg(setBodyLong, 1) && //  long
+				c.color(0) == 1 && // 2nd: white
+				c.o(0) < c.l(1) && //  open below prior low
+				c.c(0) > c.c(1)+c.avg(setEqual, 1) && //  close into prior body
+				c.c(0) <= c.c(1)+c.rb(1)*0.5, //   under the midpoint
+				-100)
+		},
+	},
+
+	CDLTristar: {
+		name: "CDLTRISTAR", sets: []cdlSet{setBodyDoji}, extra: 2,
+		fn: func(c *cdlCtx, _ *cdlState) int {
+			// TA-Lib uses the average of the 1st doji for all of them
+			doji := c.avg(setBodyDoji, 2)
+			if c.rb(2) > doji || c.rb(1) > doji || c.rb(0) > doji {
+				return 0
+			}
+			out := 0
+			if c.rbGapUp(1, 2) && c.bodyTop(0) < c.bodyTop(1) { // 2nd gaps up, 3rd is not higher than 2nd
+				out = -100
+			}
+			if c.rbGapDown(1, 2) && c.bodyBottom(0) > c.bodyBottom(1) { // 2nd gaps down, 3rd is not lower than 2nd
+				out = 100
+			}
+			return out
+		},
+	},
+
+	CDLUnique3River: {
+		name: "CDLUNIQUE3RIVER", sets: []cdlSet{setBodyShort, setBodyLong}, extra: 2,
+		fn: func(c *cdlCtx, _ *cdlState) int {
+			return cdlIf(c.rb(2) > c.avg(setBodyLong, 2) && // 1st: long
+				c.color(2) == -1 && //      black
+				c.color(1) == -1 && // 2nd: black
+				c.c(1) > c.c(2) && c.o(1) <= c.o(2) && //      harami
+				c.l(1) < c.l(2) && //      lower low
+				c.rb(0) < c.avg(setBodyShort, 0) && // 3rd: short
+				c.color(0) == 1 && //      white
+				c.o(0) > c.l(1), //      open not lower
+				100)
+		},
+	},
+
+	CDLUpsideGap2Crows: {
+		name: "CDLUPSIDEGAP2CROWS", sets: []cdlSet{setBodyShort, setBodyLong}, extra: 2,
+		fn: func(c *cdlCtx, _ *cdlState) int {
+			return cdlIf(c.color(2) == 1 && // 1st: white
+				c.rb(2) > c.avg(setBodyLong, 2) && //      long
+				c.color(1) == -1 && // 2nd: black
+				c.rb(1) <= c.avg(setBodyShort, 1) && //      short
+				c.rbGapUp(1, 2) && //      gapping up
+				c.color(0) == -1 && // 3rd: black
+				c.o(0) > c.o(1) && c.c(0) < c.c(1) && // 3rd: engulfing prior rb
+				c.c(0) > c.c(2), //      closing above 1st
+				-100)
+		},
+	},
+
+	CDLXSideGap3Methods: {
+		name: "CDLXSIDEGAP3METHODS", extra: 2,
+		fn: func(c *cdlCtx, _ *cdlState) int {
+			return cdlIf(c.color(2) == c.color(1) && // 1st and 2nd of same color
+				c.color(1) == -c.color(0) && // 3rd with opposite color
+				c.o(0) < c.bodyTop(1) && // 3rd opens within 2nd rb
+				c.o(0) > c.bodyBottom(1) &&
+				c.c(0) < c.bodyTop(2) && // 3rd closes within 1st rb
+				c.c(0) > c.bodyBottom(2) &&
+				((c.color(2) == 1 && // when 1st is white
+					c.rbGapUp(1, 2)) || // upside gap
+					(c.color(2) == -1 && // when 1st is black
+						c.rbGapDown(1, 2))), // downside gap
+				c.color(2)*100)
+		},
+	},
+}
+
+func cdlSign(up bool) int {
+	if up {
+		return 100
+	}
+	return -100
+}
+
+func cdlHarami(c *cdlCtx, small cdlSet) int {
+	if c.rb(1) <= c.avg(setBodyLong, 1) || // 1st: long
+		c.rb(0) > c.avg(small, 0) { // 2nd: short or doji
+		return 0
+	}
+	switch {
+	case c.bodyTop(0) < c.bodyTop(1) && c.bodyBottom(0) > c.bodyBottom(1): // engulfed by 1st
+		return -c.color(1) * 100
+	case c.bodyTop(0) <= c.bodyTop(1) && c.bodyBottom(0) >= c.bodyBottom(1): // partially engulfed
+		return -c.color(1) * 80
+	default:
+		return 0
+	}
+}
+
+func cdlKicking(c *cdlCtx) bool {
+	return c.color(1) == -c.color(0) && // opposite candles
+		// 1st marubozu
+		c.rb(1) > c.avg(setBodyLong, 1) &&
+		c.us(1) < c.avg(setShadowVeryShort, 1) &&
+		c.ls(1) < c.avg(setShadowVeryShort, 1) &&
+		// 2nd marubozu
+		c.rb(0) > c.avg(setBodyLong, 0) &&
+		c.us(0) < c.avg(setShadowVeryShort, 0) &&
+		c.ls(0) < c.avg(setShadowVeryShort, 0) &&
+		// gap
+		((c.color(1) == -1 && c.gapUp(0, 1)) ||
+			(c.color(1) == 1 && c.gapDown(0, 1)))
+}
+
+// cdlHikkake returns ±100 when the pattern is found and ±200 when it gets confirmed within 3 candles
+func cdlHikkake(c *cdlCtx, st *cdlState, found bool) int {
+	if found {
+		st.result = cdlSign(c.h(0) < c.h(1))
+		st.idx = c.idx
+		st.high, st.low = c.h(1), c.l(1)
+		return st.result
+	}
+
+	// search for confirmation if the pattern was no more than 3 candles ago
+	if c.idx <= st.idx+3 &&
+		((st.result > 0 && c.c(0) > st.high) || // close higher than the high of 3rd
+			(st.result < 0 && c.c(0) < st.low)) { // close lower than the low of 3rd
+		st.idx = 0
+		return st.result + cdlSign(st.result > 0)
+	}
+	return 0
+}
+
+func maxDecimal(vs ...Decimal) Decimal {
+	m := vs[0]
+	for _, v := range vs[1:] {
+		if v > m {
+			m = v
+		}
+	}
+	return m
+}
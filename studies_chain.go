@@ -0,0 +1,262 @@
+package ta
+
+import "go.oneofone.dev/ta/decimal"
+
+// Chain returns a study that feeds the output of each study to the next one,
+// for example Chain(RSI(14), EMA(9)) is EMA(9) of RSI(14) and Chain(ROC(1), StdDev(20)) is StdDev(20) of ROC(1).
+// The inputs go to the first study and every other study gets the value returned by the Update of the one before it.
+// UpdateAll returns all the values of the last study, UpdateCandle passes the candle to the first study
+// if it's a CandleStudy, otherwise it passes the close, same for Fan, Combine and Output.
+// Len and LenAll are the ones of the last study and Lookback is the sum of the lookbacks.
+func Chain(s Study, next ...Study) CandleStudy {
+	return &chain{ss: append([]Study{s}, next...)}
+}
+
+var _ CandleStudy = (*chain)(nil)
+
+type chain struct {
+	ss []Study
+}
+
+func (s *chain) Update(vs ...Decimal) Decimal      { return s.update(s.ss, vs, false)[0] }
+func (s *chain) UpdateAll(vs ...Decimal) []Decimal { return s.update(s.ss, vs, true) }
+
+func (s *chain) UpdateCandle(c *Candle) []Decimal {
+	vs := updateWithBar(s.ss[0], barFromCandle(c))
+	if len(s.ss) == 1 {
+		return vs
+	}
+	return s.update(s.ss[1:], vs[:1], true)
+}
+
+func (s *chain) update(ss []Study, vs []Decimal, all bool) []Decimal {
+	last := len(ss) - 1
+	for _, st := range ss[:last] {
+		vs = []Decimal{st.Update(vs...)}
+	}
+	if all {
+		return updateAll(ss[last], vs)
+	}
+	return []Decimal{ss[last].Update(vs...)}
+}
+
+func (s *chain) Len() int      { return s.ss[len(s.ss)-1].Len() }
+func (s *chain) LenAll() []int { return lenAll(s.ss[len(s.ss)-1]) }
+
+func (s *chain) Lookback() (lb int) {
+	for _, st := range s.ss {
+		lb += Lookback(st)
+	}
+	return lb
+}
+
+func (s *chain) Clone() Study { return clone(s) }
+
+func (s *chain) Reset() {
+	for _, st := range s.ss {
+		st.Reset()
+	}
+}
+
+func (s *chain) ToStudy() (Study, bool)         { return s, true }
+func (s *chain) ToMulti() (MultiVarStudy, bool) { return s, true }
+
+// Fan returns a study that feeds the same inputs to all the studies,
+// UpdateAll returns the value returned by the Update of each study, Update returns the first one.
+// Use Output to get a different value of a MultiVarStudy.
+func Fan(ss ...Study) CandleStudy {
+	if len(ss) == 0 {
+		panic("fan: at least one study is required")
+	}
+	return &fan{ss: ss}
+}
+
+// Combine returns a study that feeds the same inputs to all the studies and returns fn of their values,
+// vs has one value per study, in the same order.
+// For example Combine(func(vs ...Decimal) Decimal { return vs[0] / vs[1] }, ATR(14), SMA(14)) is ATR as a ratio of the price.
+func Combine(fn func(vs ...Decimal) Decimal, ss ...Study) CandleStudy {
+	if fn == nil {
+		panic("combine: fn is required")
+	}
+	f := Fan(ss...).(*fan)
+	f.fn = fn
+	return f
+}
+
+// Add returns a study that returns a + b
+func Add(a, b Study) CandleStudy {
+	return Combine(func(vs ...Decimal) Decimal { return vs[0] + vs[1] }, a, b)
+}
+
+// Sub returns a study that returns a - b
+func Sub(a, b Study) CandleStudy {
+	return Combine(func(vs ...Decimal) Decimal { return vs[0] - vs[1] }, a, b)
+}
+
+// Mul returns a study that returns a * b
+func Mul(a, b Study) CandleStudy {
+	return Combine(func(vs ...Decimal) Decimal { return vs[0] * vs[1] }, a, b)
+}
+
+// Div returns a study that returns a / b, or 0 if b is 0
+func Div(a, b Study) CandleStudy {
+	return Combine(func(vs ...Decimal) Decimal {
+		if isZero(vs[1]) {
+			return 0
+		}
+		return vs[0] / vs[1]
+	}, a, b)
+}
+
+var _ CandleStudy = (*fan)(nil)
+
+type fan struct {
+	ss []Study
+	fn func(vs ...Decimal) Decimal // nil unless it's a Combine
+}
+
+func (s *fan) Update(vs ...Decimal) Decimal { return s.UpdateAll(vs...)[0] }
+
+func (s *fan) UpdateAll(vs ...Decimal) []Decimal {
+	out := make([]Decimal, len(s.ss))
+	for i, st := range s.ss {
+		out[i] = st.Update(vs...)
+	}
+	return s.combine(out)
+}
+
+func (s *fan) UpdateCandle(c *Candle) []Decimal {
+	b := barFromCandle(c)
+	out := make([]Decimal, len(s.ss))
+	for i, st := range s.ss {
+		out[i] = updateWithBar(st, b)[0]
+	}
+	return s.combine(out)
+}
+
+func (s *fan) combine(vs []Decimal) []Decimal {
+	if s.fn == nil {
+		return vs
+	}
+	return []Decimal{s.fn(vs...)}
+}
+
+func (s *fan) Len() (n int) {
+	for _, st := range s.ss {
+		n = decimal.Max(n, st.Len())
+	}
+	return n
+}
+
+func (s *fan) LenAll() []int {
+	if s.fn != nil {
+		return []int{s.Len()}
+	}
+	out := make([]int, len(s.ss))
+	for i, st := range s.ss {
+		out[i] = st.Len()
+	}
+	return out
+}
+
+func (s *fan) Lookback() (lb int) {
+	for _, st := range s.ss {
+		lb = decimal.Max(lb, Lookback(st))
+	}
+	return lb
+}
+
+func (s *fan) Clone() Study { return clone(s) }
+
+func (s *fan) Reset() {
+	for _, st := range s.ss {
+		st.Reset()
+	}
+}
+
+func (s *fan) ToStudy() (Study, bool)         { return s, true }
+func (s *fan) ToMulti() (MultiVarStudy, bool) { return s, true }
+
+// Output returns a study that returns the value at index i of s.UpdateAll, for example Output(MACD(12, 26, 9), 1)
+// is the MACD signal line.
+func Output(s MultiVarStudy, i int) CandleStudy {
+	if i < 0 {
+		panic("output: i must be >= 0")
+	}
+	return &output{s: s, i: i}
+}
+
+var _ CandleStudy = (*output)(nil)
+
+type output struct {
+	s MultiVarStudy
+	i int
+}
+
+func (s *output) Update(vs ...Decimal) Decimal      { return s.s.UpdateAll(vs...)[s.i] }
+func (s *output) UpdateAll(vs ...Decimal) []Decimal { return []Decimal{s.Update(vs...)} }
+func (s *output) UpdateCandle(c *Candle) []Decimal {
+	return []Decimal{updateWithBar(s.s, barFromCandle(c))[s.i]}
+}
+
+func (s *output) Len() int      { return s.s.Len() }
+func (s *output) LenAll() []int { return []int{s.s.Len()} }
+func (s *output) Lookback() int { return Lookback(s.s) }
+func (s *output) Clone() Study  { return clone(s) }
+func (s *output) Reset()        { s.s.Reset() }
+
+func (s *output) ToStudy() (Study, bool)         { return s, true }
+func (s *output) ToMulti() (MultiVarStudy, bool) { return s, true }
+
+// Price returns a study that returns its input, the close for candles, it follows the same rules as CandleStudy,
+// for example Sub(Price(), SMA(20)) is the distance of the price from its SMA.
+func Price() CandleStudy {
+	return &constant{price: true}
+}
+
+// Const returns a study that always returns v, for example Mul(Const(2), ATR(14))
+func Const(v Decimal) CandleStudy {
+	return &constant{v: v}
+}
+
+var _ CandleStudy = (*constant)(nil)
+
+type constant struct {
+	v     Decimal
+	price bool
+}
+
+func (s *constant) Update(vs ...Decimal) Decimal {
+	if s.price {
+		return barFromValues(vs).close
+	}
+	return s.v
+}
+
+func (s *constant) UpdateAll(vs ...Decimal) []Decimal { return []Decimal{s.Update(vs...)} }
+func (s *constant) UpdateCandle(c *Candle) []Decimal  { return []Decimal{s.Update(c.Close)} }
+
+func (s *constant) Len() int      { return 0 }
+func (s *constant) LenAll() []int { return []int{0} }
+func (s *constant) Lookback() int { return 0 }
+func (s *constant) Clone() Study  { return clone(s) }
+func (s *constant) Reset()        {}
+
+func (s *constant) ToStudy() (Study, bool)         { return s, true }
+func (s *constant) ToMulti() (MultiVarStudy, bool) { return s, true }
+
+// updateAll returns all the values of s if it supports UpdateAll, otherwise the value of Update
+func updateAll(s Study, vs []Decimal) []Decimal {
+	if ms, ok := s.ToMulti(); ok {
+		return ms.UpdateAll(vs...)
+	}
+	return []Decimal{s.Update(vs...)}
+}
+
+// lenAll returns s.LenAll if s is a MultiVarStudy, otherwise s.Len
+func lenAll(s Study) []int {
+	if ms, ok := s.ToMulti(); ok {
+		return ms.LenAll()
+	}
+	return []int{s.Len()}
+}
@@ -0,0 +1,120 @@
+package ta
+
+import (
+	"testing"
+
+	"go.oneofone.dev/ta/decimal"
+)
+
+func TestChain(t *testing.T) {
+	var (
+		ch       = Chain(RSI(14), EMA(9))
+		rsi, ema = RSI(14), EMA(9)
+	)
+	for i := 0; i < testClose.Len(); i++ {
+		v := testClose.Get(i)
+		if exp, got := ema.Update(rsi.Update(v)), ch.Update(v); exp != got {
+			t.Fatalf("[%d] expected %v, got %v", i, exp, got)
+		}
+	}
+
+	// ApplyStudy returns the last Len() values of the last study
+	roc, sd := ROC(1), StdDev(20)
+	exp := NewCapped(sd.Len())
+	for i := 0; i < testClose.Len(); i++ {
+		exp.Append(sd.Update(roc.Update(testClose.Get(i))))
+	}
+	if got := ApplyStudy(Chain(ROC(1), StdDev(20)), testClose); !got.Equal(exp) {
+		t.Fatalf("expected %v, got %v", exp.Floats(), got.Floats())
+	}
+
+	// the candle goes to the first study, UpdateAll returns all the outputs of the last one
+	var (
+		atr   = ATR(14)
+		macd  = MACD(3, 5, 2)
+		chain = Chain(ATR(14), MACD(3, 5, 2))
+	)
+	for i, c := range testCandles() {
+		exp := macd.UpdateAll(atr.Update(c.High, c.Low, c.Close))
+		if got := chain.UpdateCandle(c); !sameBits(exp, got) {
+			t.Fatalf("[%d] expected %v, got %v", i, exp, got)
+		}
+	}
+	if lens := chain.LenAll(); len(lens) != 3 {
+		t.Fatalf("expected 3 outputs, got %v", lens)
+	}
+	if lb := Lookback(chain); lb != Lookback(ATR(14))+Lookback(MACD(3, 5, 2)) {
+		t.Fatalf("unexpected lookback %d", lb)
+	}
+}
+
+func TestFanCombine(t *testing.T) {
+	var (
+		f           = Fan(SMA(5), EMA(5), Output(MACD(12, 26, 9), 1))
+		sub         = Sub(Price(), SMA(20))
+		ratio       = Div(ATR(14), Mul(Const(2), SMA(14)))
+		sma, ema    = SMA(5), EMA(5)
+		macd, sma20 = MACD(12, 26, 9), SMA(20)
+		atr, sma14  = ATR(14), SMA(14)
+	)
+	for i, c := range testCandles() {
+		exp := []Decimal{sma.Update(c.Close), ema.Update(c.Close), macd.UpdateAll(c.Close)[1]}
+		if got := f.UpdateAll(c.Close); !sameBits(exp, got) {
+			t.Fatalf("[%d] fan: expected %v, got %v", i, exp, got)
+		}
+
+		if exp, got := c.Close-sma20.Update(c.Close), sub.UpdateCandle(c)[0]; exp != got {
+			t.Fatalf("[%d] sub: expected %v, got %v", i, exp, got)
+		}
+
+		exp2 := atr.Update(c.High, c.Low, c.Close) / (2 * sma14.Update(c.Close))
+		if got := ratio.UpdateCandle(c)[0]; !decimal.EqualApprox(exp2.Float(), got.Float(), 1e-12) {
+			t.Fatalf("[%d] ratio: expected %v, got %v", i, exp2, got)
+		}
+	}
+
+	if lens := f.LenAll(); len(lens) != 3 || lens[2] != MACD(12, 26, 9).Len() {
+		t.Fatalf("unexpected lens %v", lens)
+	}
+	if lb := Lookback(f); lb != 33 {
+		t.Fatalf("expected lookback 33, got %d", lb)
+	}
+	if lb := Lookback(ratio); lb != 14 {
+		t.Fatalf("expected lookback 14, got %d", lb)
+	}
+
+	if v := Div(Const(1), Const(0)).Update(1); v != 0 {
+		t.Fatalf("expected 0, got %v", v)
+	}
+}
+
+func TestComposedStudy(t *testing.T) {
+	exp := ApplyStudy(Sub(EMA(9), EMA(21)), testClose)
+	if got := ApplyStudy(LockedStudy(Sub(EMA(9), EMA(21))), testClose); !got.Equal(exp) {
+		t.Fatalf("locked: expected %v, got %v", exp.Floats(), got.Floats())
+	}
+
+	multi := ApplyMultiVarStudy(Fan(EMA(9), EMA(21)), testClose)
+	for i, s := range []Study{EMA(9), EMA(21)} {
+		if exp := ApplyStudy(s, testClose); !multi[i].Equal(exp) {
+			t.Fatalf("[%d] expected %v, got %v", i, exp.Floats(), multi[i].Floats())
+		}
+	}
+
+	st := StreamFromStudy(Sub(EMA(9), EMA(21)), true)
+	done := make(chan *TA)
+	go func() {
+		out := NewCapped(exp.Len())
+		for v := range st.Chan() {
+			out.Append(v)
+		}
+		done <- out
+	}()
+	for i := 0; i < testClose.Len(); i++ {
+		st.Update(testClose.Get(i))
+	}
+	st.Close()
+	if got := <-done; !got.Equal(exp) {
+		t.Fatalf("stream: expected %v, got %v", exp.Floats(), got.Floats())
+	}
+}
@@ -0,0 +1,106 @@
+package ta
+
+import "reflect"
+
+// clone returns a deep copy of v, pointers that are shared inside v are shared the same way in the copy,
+// funcs and pointers to types of other packages, like *time.Location, are shared with v, sync types are reset.
+func clone[T any](v T) T {
+	c := cloner{seen: map[clonePtr]reflect.Value{}}
+	src := reflect.ValueOf(&v).Elem()
+	dst := reflect.New(src.Type()).Elem()
+	c.copy(dst, src)
+	return dst.Interface().(T)
+}
+
+type clonePtr struct {
+	p uintptr
+	t reflect.Type
+}
+
+type cloner struct {
+	seen map[clonePtr]reflect.Value // the copies of the pointers cloned so far
+}
+
+// copy copies src to dst, both must be addressable
+func (c *cloner) copy(dst, src reflect.Value) {
+	switch src.Kind() {
+	case reflect.Ptr:
+		if src.IsNil() {
+			return
+		}
+		if !cloneType(src.Type().Elem()) {
+			dst.Set(src)
+			return
+		}
+		key := clonePtr{src.Pointer(), src.Type()}
+		if p, ok := c.seen[key]; ok {
+			dst.Set(p)
+			return
+		}
+		p := reflect.New(src.Type().Elem())
+		c.seen[key] = p
+		c.copy(p.Elem(), src.Elem())
+		dst.Set(p)
+
+	case reflect.Interface:
+		if src.IsNil() {
+			return
+		}
+		// interface values aren't addressable
+		e := reflect.New(src.Elem().Type()).Elem()
+		e.Set(src.Elem())
+		v := reflect.New(e.Type()).Elem()
+		c.copy(v, e)
+		dst.Set(v)
+
+	case reflect.Slice:
+		if src.IsNil() {
+			return
+		}
+		s := reflect.MakeSlice(src.Type(), src.Len(), src.Cap())
+		for i := 0; i < src.Len(); i++ {
+			c.copy(s.Index(i), src.Index(i))
+		}
+		dst.Set(s)
+
+	case reflect.Array:
+		for i := 0; i < src.Len(); i++ {
+			c.copy(dst.Index(i), src.Index(i))
+		}
+
+	case reflect.Map:
+		if src.IsNil() {
+			return
+		}
+		m := reflect.MakeMapWithSize(src.Type(), src.Len())
+		for it := src.MapRange(); it.Next(); {
+			k, v := reflect.New(src.Type().Key()).Elem(), reflect.New(src.Type().Elem()).Elem()
+			v.Set(it.Value())
+			cv := reflect.New(v.Type()).Elem()
+			c.copy(cv, v)
+			k.Set(it.Key())
+			m.SetMapIndex(k, cv)
+		}
+		dst.Set(m)
+
+	case reflect.Struct:
+		switch t := src.Type(); {
+		case t.PkgPath() == "sync":
+		case !cloneType(t):
+			exported(dst).Set(exported(src))
+		default:
+			for i := 0; i < src.NumField(); i++ {
+				c.copy(exported(dst.Field(i)), exported(src.Field(i)))
+			}
+		}
+
+	default:
+		dst.Set(src)
+	}
+}
+
+// cloneType returns true for the types that are deep copied, the types of this package and unnamed ones
+func cloneType(t reflect.Type) bool {
+	pkg := t.PkgPath()
+	return pkg == "" || pkg == taType.PkgPath()
+}
@@ -0,0 +1,124 @@
+package ta
+
+import (
+	"testing"
+	"time"
+)
+
+func TestStudyClone(t *testing.T) {
+	candles := testCandles()
+	half := len(candles) / 2
+	for name, fn := range stateStudies() {
+		orig, ref := fn(), fn()
+		for _, c := range candles[:half] {
+			updateState(name, orig, c)
+			updateState(name, ref, c)
+		}
+
+		cp := orig.Clone()
+		if _, ok := orig.ToMulti(); ok {
+			if _, ok := cp.(MultiVarStudy); !ok {
+				t.Fatalf("%s: the clone of a MultiVarStudy must be a MultiVarStudy", name)
+			}
+		}
+
+		// updating the clone doesn't change the original
+		for _, c := range candles[half:] {
+			updateState(name, cp, c)
+		}
+		for i, c := range candles[half:] {
+			if exp, got := updateState(name, ref, c), updateState(name, orig, c); !sameBits(exp, got) {
+				t.Fatalf("%s [%d]: expected %v, got %v", name, i, exp, got)
+			}
+		}
+	}
+}
+
+func TestStudyClonePendingRevision(t *testing.T) {
+	orig := Revisable(SMA(3))
+	orig.Update(1)
+	orig.Update(2)
+	orig.Revise(10)
+
+	cp := orig.Clone().(RevisableStudy)
+	if v := cp.Revise(3)[0]; v != 2 {
+		t.Fatalf("expected 2, got %v", v)
+	}
+	cp.Commit()
+	if v := cp.Update(4); v != 3 {
+		t.Fatalf("expected 3, got %v", v)
+	}
+
+	// the original still has its own pending revision
+	if v := orig.Revise(6)[0]; v != 3 {
+		t.Fatalf("expected 3, got %v", v)
+	}
+	orig.Commit()
+	if v := orig.Update(9); v != 17./3 {
+		t.Fatalf("expected %v, got %v", 17./3, v)
+	}
+}
+
+func TestStudyCloneBranch(t *testing.T) {
+	candles := testCandles()
+	half := len(candles) / 2
+	for name, fn := range stateStudies() {
+		orig := fn()
+		for _, c := range candles[:half] {
+			updateState(name, orig, c)
+		}
+		cp := orig.Clone()
+		for i, c := range candles[half:] {
+			if exp, got := updateState(name, orig, c), updateState(name, cp, c); !sameBits(exp, got) {
+				t.Fatalf("%s [%d]: expected %v, got %v", name, i, exp, got)
+			}
+		}
+	}
+}
+
+func TestStudyReset(t *testing.T) {
+	candles := testCandles()
+	for name, fn := range stateStudies() {
+		s := fn()
+		for _, c := range candles[:100] {
+			updateState(name, s, c)
+		}
+		s.Reset()
+
+		ref := fn()
+		for i, c := range candles {
+			if exp, got := updateState(name, ref, c), updateState(name, s, c); !sameBits(exp, got) {
+				t.Fatalf("%s [%d]: expected %v, got %v", name, i, exp, got)
+			}
+		}
+	}
+
+	var (
+		t0  = time.Date(2021, 3, 1, 9, 30, 0, 0, time.UTC)
+		mtf = Readiness(MultiTimeframeAt(func() Study { return SMA(2) }, time.Hour, nil, false))
+	)
+	for i, c := range candles[:10] {
+		mtf.UpdateCandleAt(t0.Add(time.Duration(i)*30*time.Minute), c)
+	}
+	mtf.Reset()
+	if mtf.Ready() || mtf.Count() != 0 {
+		t.Fatal("expected a reset study")
+	}
+	// the forming bar is gone, so an older timestamp starts a new bar
+	mtf.UpdateCandleAt(t0, candles[0])
+	if f := mtf.(*tracked).s.(TimeframeStudy).Forming(); f == nil || *f != *candles[0] {
+		t.Fatalf("unexpected forming bar %v", f)
+	}
+}
+
+func TestTACopy(t *testing.T) {
+	ta := NewCapped(3).Append(1, 2, 3, 4)
+	cp := ta.Copy()
+	cp.Append(5)
+	if exp := []Decimal{2, 3, 4}; !ta.Equal(&TA{v: exp}) {
+		t.Fatalf("expected %v, got %v", exp, ta.Floats())
+	}
+	if exp := []Decimal{3, 4, 5}; !cp.Equal(&TA{v: exp}) {
+		t.Fatalf("expected %v, got %v", exp, cp.Floats())
+	}
+}
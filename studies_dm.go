@@ -0,0 +1,264 @@
+package ta
+
+const (
+	dmiPlus uint8 = iota
+	dmiMinus
+	dmiDX
+	dmiADX
+	dmiADXR
+)
+
+// ADX - Average Directional Movement Index
+// Update returns ADX
+// UpdateAll returns [+DI, -DI, ADX]
+func ADX(period int) CandleStudy {
+	return newDMI(period, dmiADX)
+}
+
+// ADXR - Average Directional Movement Index Rating
+// Update returns ADXR
+// UpdateAll returns [+DI, -DI, ADX, ADXR]
+func ADXR(period int) CandleStudy {
+	return newDMI(period, dmiADXR)
+}
+
+// DX - Directional Movement Index
+// Update returns DX
+// UpdateAll returns [+DI, -DI, DX]
+func DX(period int) CandleStudy {
+	return newDMI(period, dmiDX)
+}
+
+// PlusDI - Plus Directional Indicator
+// same as DX, except Update returns +DI
+func PlusDI(period int) CandleStudy {
+	return newDMI(period, dmiPlus)
+}
+
+// MinusDI - Minus Directional Indicator
+// same as DX, except Update returns -DI
+func MinusDI(period int) CandleStudy {
+	return newDMI(period, dmiMinus)
+}
+
+func newDMI(period int, mode uint8) *dmi {
+	checkPeriod(period, 2)
+	s := &dmi{
+		period: period,
+		mode:   mode,
+	}
+	if mode == dmiADXR {
+		s.adxr = NewCapped(period)
+	}
+	return s
+}
+
+var _ CandleStudy = (*dmi)(nil)
+
+// dmi is a port of TA-Lib's Wilder directional movement functions,
+// the DM and TR sums are smoothed with Wilder's smoothing, the first period-1 bars are just summed up.
+type dmi struct {
+	adxr *TA
+
+	prevHigh  Decimal
+	prevLow   Decimal
+	prevClose Decimal
+
+	plusDM  Decimal
+	minusDM Decimal
+	tr      Decimal
+
+	plusDI  Decimal
+	minusDI Decimal
+	dx      Decimal
+	adx     Decimal
+	sumDX   Decimal
+
+	period int
+	count  int
+	mode   uint8
+}
+
+func (s *dmi) Update(vs ...Decimal) Decimal {
+	out := s.UpdateAll(vs...)
+	switch s.mode {
+	case dmiPlus:
+		return out[0]
+	case dmiMinus:
+		return out[1]
+	case dmiADXR:
+		return out[3]
+	default:
+		return out[2]
+	}
+}
+
+func (s *dmi) UpdateAll(vs ...Decimal) []Decimal { return s.update(barFromValues(vs)) }
+func (s *dmi) UpdateCandle(c *Candle) []Decimal  { return s.update(barFromCandle(c)) }
+
+func (s *dmi) update(b bar) []Decimal {
+	count := s.count
+	s.count++
+	if count == 0 {
+		s.prevHigh, s.prevLow, s.prevClose = b.high, b.low, b.close
+		return s.values()
+	}
+
+	var (
+		diffP = b.high - s.prevHigh
+		diffM = s.prevLow - b.low
+		tr    = b.trueRange(s.prevClose)
+		n     = Decimal(s.period)
+
+		plusDM, minusDM Decimal
+	)
+
+	s.prevHigh, s.prevLow, s.prevClose = b.high, b.low, b.close
+
+	if diffM > 0 && diffP < diffM {
+		minusDM = diffM
+	} else if diffP > 0 && diffP > diffM {
+		plusDM = diffP
+	}
+
+	if count < s.period {
+		s.plusDM += plusDM
+		s.minusDM += minusDM
+		s.tr += tr
+		return s.values()
+	}
+
+	s.plusDM = s.plusDM - s.plusDM/n + plusDM
+	s.minusDM = s.minusDM - s.minusDM/n + minusDM
+	s.tr = s.tr - s.tr/n + tr
+
+	var dx Decimal
+	ok := !isZero(s.tr)
+	if ok {
+		s.plusDI = 100 * (s.plusDM / s.tr)
+		s.minusDI = 100 * (s.minusDM / s.tr)
+		sum := s.minusDI + s.plusDI
+		if ok = !isZero(sum); ok {
+			dx = 100 * ((s.minusDI - s.plusDI).Abs() / sum)
+			s.dx = dx
+		}
+	} else {
+		s.plusDI, s.minusDI = 0, 0
+	}
+
+	switch adxIdx := count - s.period; {
+	case adxIdx < s.period:
+		// the first ADX is the average of the first period DX values
+		s.sumDX += dx
+		s.adx = s.sumDX / Decimal(adxIdx+1)
+	case ok:
+		s.adx = (s.adx*(n-1) + dx) / n
+	}
+
+	if s.adxr != nil && count >= 2*s.period-1 {
+		s.adxr.Update(s.adx)
+	}
+
+	return s.values()
+}
+
+func (s *dmi) values() []Decimal {
+	switch s.mode {
+	case dmiADX:
+		return []Decimal{s.plusDI, s.minusDI, s.adx}
+	case dmiADXR:
+		// the oldest value is from period-1 bars ago
+		return []Decimal{s.plusDI, s.minusDI, s.adx, (s.adx + s.adxr.Get(0)) / 2}
+	default:
+		return []Decimal{s.plusDI, s.minusDI, s.dx}
+	}
+}
+
+func (s *dmi) Len() int { return s.period }
+
+func (s *dmi) Lookback() int {
+	switch s.mode {
+	case dmiADX:
+		return 2*s.period - 1
+	case dmiADXR:
+		return 3*s.period - 2
+	default:
+		return s.period
+	}
+}
+func (s *dmi) Clone() Study { return clone(s) }
+func (s *dmi) Reset()       { *s = *newDMI(s.period, s.mode) }
+func (s *dmi) LenAll() []int {
+	ln := s.period
+	if s.mode == dmiADXR {
+		return []int{ln, ln, ln, ln}
+	}
+	return []int{ln, ln, ln}
+}
+
+func (s *dmi) ToStudy() (Study, bool)         { return s, true }
+func (s *dmi) ToMulti() (MultiVarStudy, bool) { return s, true }
+
+// Aroon returns an Aroon study, it uses the high and low of the last period+1 bars
+// Update returns the Aroon Oscillator
+// UpdateAll returns [up, down, oscillator]
+func Aroon(period int) CandleStudy {
+	checkPeriod(period, 2)
+	return &aroon{
+		high:   newRing(period + 1),
+		low:    newRing(period + 1),
+		period: period,
+	}
+}
+
+// AroonOsc - Aroon Oscillator, alias for Aroon(period)
+func AroonOsc(period int) CandleStudy {
+	return Aroon(period)
+}
+
+var _ CandleStudy = (*aroon)(nil)
+
+type aroon struct {
+	high   *TA
+	low    *TA
+	period int
+}
+
+func (s *aroon) Update(vs ...Decimal) Decimal      { return s.UpdateAll(vs...)[2] }
+func (s *aroon) UpdateAll(vs ...Decimal) []Decimal { return s.update(barFromValues(vs)) }
+func (s *aroon) UpdateCandle(c *Candle) []Decimal  { return s.update(barFromCandle(c)) }
+
+func (s *aroon) update(b bar) []Decimal {
+	s.high.Update(b.high)
+	s.low.Update(b.low)
+
+	// ties go to the most recent bar, same as TA-Lib
+	hi, lo, ln := 0, 0, s.high.Len()
+	for i := 1; i < ln; i++ {
+		if s.high.Get(i) >= s.high.Get(hi) {
+			hi = i
+		}
+		if s.low.Get(i) <= s.low.Get(lo) {
+			lo = i
+		}
+	}
+
+	var (
+		f    = 100 / Decimal(s.period)
+		up   = f * Decimal(s.period-(ln-1-hi))
+		down = f * Decimal(s.period-(ln-1-lo))
+	)
+	return []Decimal{up, down, up - down}
+}
+
+func (s *aroon) Len() int      { return s.period }
+func (s *aroon) Lookback() int { return s.period }
+func (s *aroon) Clone() Study  { return clone(s) }
+func (s *aroon) Reset()        { *s = *Aroon(s.period).(*aroon) }
+func (s *aroon) LenAll() []int {
+	ln := s.period
+	return []int{ln, ln, ln}
+}
+
+func (s *aroon) ToStudy() (Study, bool)         { return s, true }
+func (s *aroon) ToMulti() (MultiVarStudy, bool) { return s, true }
@@ -0,0 +1,646 @@
+package ta
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"go.oneofone.dev/ta/decimal"
+)
+
+// ExprError is returned by ParseExpr for invalid expressions
+type ExprError struct {
+	Expr string // the expression
+	Pos  int    // the byte offset of the error in Expr
+	Msg  string
+}
+
+func (e *ExprError) Error() string {
+	return fmt.Sprintf("ta: invalid expression at column %d: %s", e.Pos+1, e.Msg)
+}
+
+// ParseExpr compiles an expression like `crossover(ema(close, 9), ema(close, 21))` into a study,
+// the study is updated with a candle at a time, with UpdateCandle or [open, high, low, close, volume] values,
+// and Update returns the value of the expression for that candle.
+//
+// The language supports:
+//   - numbers and the candle fields: open, high, low, close, volume, hl2, hlc3 and ohlc4
+//   - arithmetic: + - * /, a division by 0 returns 0
+//   - comparisons: < <= > >= == !=, and the logical operators: && (and), || (or), ! (not),
+//     true is 1 and false is 0
+//   - lag: x[n] is the value of x n candles ago, n must be a constant
+//   - crossover(a, b) and crossunder(a, b), 1 on the candle a crosses over or under b, 0 otherwise
+//   - abs(x)
+//   - the studies of this package by name, the input comes first for the ones that don't use candles,
+//     the parameters must be constants, for example ema(close, 9), rsi(hl2, 14), atr(14) or sar(0.02, 0.2)
+//
+// Names are case insensitive, the error of an invalid expression is an *ExprError with the position of the problem.
+func ParseExpr(src string) (s CandleStudy, err error) {
+	p := &exprParser{src: src}
+	if err = p.next(); err != nil {
+		return
+	}
+	n, err := p.parseOr()
+	if err != nil {
+		return
+	}
+	if p.tok.kind != tokEOF {
+		return nil, p.errorf(p.tok.pos, "unexpected %s", p.tok)
+	}
+	if cs, ok := n.s.(CandleStudy); ok {
+		return cs, nil
+	}
+	return OnCandles(n.s, nil), nil
+}
+
+// MustParseExpr same as ParseExpr, but panics on error
+func MustParseExpr(src string) CandleStudy {
+	s, err := ParseExpr(src)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+type exprArg struct {
+	name  string
+	float bool
+}
+
+type exprFunc struct {
+	src  bool      // the first argument is the input of the study
+	args []exprArg // the parameters of the study
+	fn   func(p []Decimal) Study
+}
+
+var (
+	argPeriod = []exprArg{{name: "period"}}
+	argMACD   = []exprArg{{name: "fast"}, {name: "slow"}, {name: "signal"}}
+	argBBands = []exprArg{{name: "period"}, {name: "mult", float: true}}
+	argStoch  = []exprArg{{name: "k"}, {name: "slowing"}, {name: "d"}}
+
+	exprFields = map[string]CandleField{
+		"open":   FieldOpen,
+		"high":   FieldHigh,
+		"low":    FieldLow,
+		"volume": FieldVolume,
+		"hl2":    FieldHL2,
+		"hlc3":   FieldHLC3,
+		"ohlc4":  FieldOHLC4,
+	}
+
+	exprFuncs = map[string]exprFunc{
+		"sma":    {true, argPeriod, func(p []Decimal) Study { return SMA(int(p[0])) }},
+		"ema":    {true, argPeriod, func(p []Decimal) Study { return EMA(int(p[0])) }},
+		"wma":    {true, argPeriod, func(p []Decimal) Study { return WMA(int(p[0])) }},
+		"dema":   {true, argPeriod, func(p []Decimal) Study { return DEMA(int(p[0])) }},
+		"tema":   {true, argPeriod, func(p []Decimal) Study { return TEMA(int(p[0])) }},
+		"trima":  {true, argPeriod, func(p []Decimal) Study { return TRIMA(int(p[0])) }},
+		"kama":   {true, argPeriod, func(p []Decimal) Study { return KAMA(int(p[0])) }},
+		"t3":     {true, argPeriod, func(p []Decimal) Study { return T3(int(p[0])) }},
+		"hma":    {true, argPeriod, func(p []Decimal) Study { return HMA(int(p[0])) }},
+		"zlema":  {true, argPeriod, func(p []Decimal) Study { return ZLEMA(int(p[0])) }},
+		"wilder": {true, argPeriod, func(p []Decimal) Study { return WilderMA(int(p[0])) }},
+		"rsi":    {true, argPeriod, func(p []Decimal) Study { return RSI(int(p[0])) }},
+		"cmo":    {true, argPeriod, func(p []Decimal) Study { return CMO(int(p[0])) }},
+		"mom":    {true, argPeriod, func(p []Decimal) Study { return MOM(int(p[0])) }},
+		"roc":    {true, argPeriod, func(p []Decimal) Study { return ROC(int(p[0])) }},
+		"rocp":   {true, argPeriod, func(p []Decimal) Study { return ROCP(int(p[0])) }},
+		"rocr":   {true, argPeriod, func(p []Decimal) Study { return ROCR(int(p[0])) }},
+		"trix":   {true, argPeriod, func(p []Decimal) Study { return TRIX(int(p[0])) }},
+		"stddev": {true, argPeriod, func(p []Decimal) Study { return StdDev(int(p[0])) }},
+		"min":    {true, argPeriod, func(p []Decimal) Study { return Min(int(p[0])) }},
+		"max":    {true, argPeriod, func(p []Decimal) Study { return Max(int(p[0])) }},
+		"linreg": {true, argPeriod, func(p []Decimal) Study { return LinearReg(int(p[0])) }},
+		"slope":  {true, argPeriod, func(p []Decimal) Study { return LinearRegSlope(int(p[0])) }},
+		"tsf":    {true, argPeriod, func(p []Decimal) Study { return TSF(int(p[0])) }},
+
+		"macd":       {true, argMACD, func(p []Decimal) Study { return macdOutput(p, 0) }},
+		"macdsignal": {true, argMACD, func(p []Decimal) Study { return macdOutput(p, 1) }},
+		"macdhist":   {true, argMACD, func(p []Decimal) Study { return macdOutput(p, 2) }},
+		"bbupper":    {true, argBBands, func(p []Decimal) Study { return bbandsOutput(p, 0) }},
+		"bbmiddle":   {true, argBBands, func(p []Decimal) Study { return bbandsOutput(p, 1) }},
+		"bblower":    {true, argBBands, func(p []Decimal) Study { return bbandsOutput(p, 2) }},
+
+		"atr":      {false, argPeriod, func(p []Decimal) Study { return ATR(int(p[0])) }},
+		"natr":     {false, argPeriod, func(p []Decimal) Study { return NATR(int(p[0])) }},
+		"cci":      {false, argPeriod, func(p []Decimal) Study { return CCI(int(p[0])) }},
+		"willr":    {false, argPeriod, func(p []Decimal) Study { return WilliamsR(int(p[0])) }},
+		"adx":      {false, argPeriod, func(p []Decimal) Study { return ADX(int(p[0])) }},
+		"adxr":     {false, argPeriod, func(p []Decimal) Study { return ADXR(int(p[0])) }},
+		"dx":       {false, argPeriod, func(p []Decimal) Study { return DX(int(p[0])) }},
+		"plusdi":   {false, argPeriod, func(p []Decimal) Study { return PlusDI(int(p[0])) }},
+		"minusdi":  {false, argPeriod, func(p []Decimal) Study { return MinusDI(int(p[0])) }},
+		"mfi":      {false, argPeriod, func(p []Decimal) Study { return MFI(int(p[0])) }},
+		"aroonosc": {false, argPeriod, func(p []Decimal) Study { return AroonOsc(int(p[0])) }},
+		"trange":   {false, nil, func(p []Decimal) Study { return TRange() }},
+		"obv":      {false, nil, func(p []Decimal) Study { return OBV() }},
+		"bop":      {false, nil, func(p []Decimal) Study { return BOP() }},
+		"ad":       {false, nil, func(p []Decimal) Study { return AD() }},
+
+		"sar": {false, []exprArg{{name: "accel", float: true}, {name: "max", float: true}}, func(p []Decimal) Study {
+			return SAR(p[0], p[1])
+		}},
+		"ultosc": {false, []exprArg{{name: "period1"}, {name: "period2"}, {name: "period3"}}, func(p []Decimal) Study {
+			return UltimateOscillator(int(p[0]), int(p[1]), int(p[2]))
+		}},
+		"stochk": {false, argStoch, func(p []Decimal) Study { return stochOutput(p, 0) }},
+		"stochd": {false, argStoch, func(p []Decimal) Study { return stochOutput(p, 1) }},
+		"supertrend": {false, []exprArg{{name: "period"}, {name: "mult", float: true}}, func(p []Decimal) Study {
+			return SuperTrend(int(p[0]), p[1], nil)
+		}},
+	}
+)
+
+func macdOutput(p []Decimal, i int) Study {
+	return Output(MACD(int(p[0]), int(p[1]), int(p[2])), i)
+}
+
+func bbandsOutput(p []Decimal, i int) Study {
+	return Output(BBandsLimits(int(p[0]), p[1], p[1]), i)
+}
+
+func stochOutput(p []Decimal, i int) Study {
+	return Output(StochSlow(int(p[0]), int(p[1]), int(p[2]), nil), i)
+}
+
+const (
+	tokEOF = iota
+	tokNum
+	tokIdent
+	tokOp
+)
+
+type exprToken struct {
+	kind int
+	pos  int
+	text string
+	num  Decimal
+}
+
+func (t exprToken) String() string {
+	if t.kind == tokEOF {
+		return "end of expression"
+	}
+	return strconv.Quote(t.text)
+}
+
+type exprParser struct {
+	src string
+	off int
+	tok exprToken
+}
+
+// exprNode is a compiled sub expression and where it starts
+type exprNode struct {
+	s   Study
+	pos int
+}
+
+func (p *exprParser) errorf(pos int, format string, args ...interface{}) error {
+	return &ExprError{Expr: p.src, Pos: pos, Msg: fmt.Sprintf(format, args...)}
+}
+
+// next reads the next token into p.tok
+func (p *exprParser) next() error {
+	src := p.src
+	for p.off < len(src) && strings.IndexByte(" \t\r\n", src[p.off]) != -1 {
+		p.off++
+	}
+
+	start := p.off
+	p.tok = exprToken{pos: start}
+	if start == len(src) {
+		return nil
+	}
+
+	switch c := src[start]; {
+	case c >= '0' && c <= '9' || c == '.':
+		for p.off < len(src) && (isDigit(src[p.off]) || src[p.off] == '.') {
+			p.off++
+		}
+		if p.off < len(src) && (src[p.off] == 'e' || src[p.off] == 'E') {
+			p.off++
+			if p.off < len(src) && (src[p.off] == '+' || src[p.off] == '-') {
+				p.off++
+			}
+			for p.off < len(src) && isDigit(src[p.off]) {
+				p.off++
+			}
+		}
+		text := src[start:p.off]
+		f, err := strconv.ParseFloat(text, 64)
+		if err != nil {
+			return p.errorf(start, "invalid number %q", text)
+		}
+		p.tok.kind, p.tok.text, p.tok.num = tokNum, text, Decimal(f)
+
+	case c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z':
+		for p.off < len(src) && (src[p.off] == '_' || isDigit(src[p.off]) || isLetter(src[p.off])) {
+			p.off++
+		}
+		p.tok.kind, p.tok.text = tokIdent, strings.ToLower(src[start:p.off])
+		switch p.tok.text {
+		case "and":
+			p.tok.kind, p.tok.text = tokOp, "&&"
+		case "or":
+			p.tok.kind, p.tok.text = tokOp, "||"
+		case "not":
+			p.tok.kind, p.tok.text = tokOp, "!"
+		}
+
+	default:
+		for _, op := range []string{"<=", ">=", "==", "!=", "&&", "||", "+", "-", "*", "/", "(", ")", "[", "]", ",", "<", ">", "!"} {
+			if strings.HasPrefix(src[start:], op) {
+				p.off += len(op)
+				p.tok.kind, p.tok.text = tokOp, op
+				return nil
+			}
+		}
+		if c == '=' {
+			return p.errorf(start, `unexpected "=", did you mean "=="?`)
+		}
+		return p.errorf(start, "unexpected character %q", src[start])
+	}
+	return nil
+}
+
+func isDigit(c byte) bool  { return c >= '0' && c <= '9' }
+func isLetter(c byte) bool { return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' }
+
+// accept consumes the current token if it's the operator op
+func (p *exprParser) accept(op string) (bool, error) {
+	if p.tok.kind != tokOp || p.tok.text != op {
+		return false, nil
+	}
+	return true, p.next()
+}
+
+func (p *exprParser) expect(op string) error {
+	if ok, err := p.accept(op); ok || err != nil {
+		return err
+	}
+	return p.errorf(p.tok.pos, "expected %q, got %s", op, p.tok)
+}
+
+// parseBinary parses a left associative chain of the operators ops, the operands are parsed by operand
+func (p *exprParser) parseBinary(operand func() (exprNode, error), ops ...string) (exprNode, error) {
+	lhs, err := operand()
+	if err != nil {
+		return lhs, err
+	}
+	for p.tok.kind == tokOp {
+		op := p.tok.text
+		if !contains(ops, op) {
+			break
+		}
+		if err = p.next(); err != nil {
+			return lhs, err
+		}
+		rhs, err := operand()
+		if err != nil {
+			return lhs, err
+		}
+		lhs = exprNode{binaryOp(op, lhs.s, rhs.s), lhs.pos}
+	}
+	return lhs, nil
+}
+
+func (p *exprParser) parseOr() (exprNode, error)  { return p.parseBinary(p.parseAnd, "||") }
+func (p *exprParser) parseAnd() (exprNode, error) { return p.parseBinary(p.parseCmp, "&&") }
+func (p *exprParser) parseAdd() (exprNode, error) { return p.parseBinary(p.parseMul, "+", "-") }
+func (p *exprParser) parseMul() (exprNode, error) { return p.parseBinary(p.parseUnary, "*", "/") }
+
+// comparisons don't chain, a < b < c is an error
+func (p *exprParser) parseCmp() (exprNode, error) {
+	lhs, err := p.parseAdd()
+	if err != nil || p.tok.kind != tokOp {
+		return lhs, err
+	}
+	op := p.tok.text
+	if !isCmp(op) {
+		return lhs, nil
+	}
+	if err = p.next(); err != nil {
+		return lhs, err
+	}
+	rhs, err := p.parseAdd()
+	if err != nil {
+		return lhs, err
+	}
+	if p.tok.kind == tokOp && isCmp(p.tok.text) {
+		return lhs, p.errorf(p.tok.pos, "comparisons can't be chained, use && instead")
+	}
+	return exprNode{binaryOp(op, lhs.s, rhs.s), lhs.pos}, nil
+}
+
+func (p *exprParser) parseUnary() (exprNode, error) {
+	pos := p.tok.pos
+	for _, op := range []string{"-", "+", "!"} {
+		ok, err := p.accept(op)
+		if err != nil {
+			return exprNode{}, err
+		}
+		if !ok {
+			continue
+		}
+		n, err := p.parseUnary()
+		if err != nil || op == "+" {
+			return n, err
+		}
+		return exprNode{unaryOp(op, n.s), pos}, nil
+	}
+	return p.parsePostfix()
+}
+
+// parsePostfix parses the lag operator, x[n]
+func (p *exprParser) parsePostfix() (exprNode, error) {
+	n, err := p.parsePrimary()
+	for err == nil {
+		var ok bool
+		if ok, err = p.accept("["); !ok || err != nil {
+			break
+		}
+		pos := p.tok.pos
+		var lag exprNode
+		if lag, err = p.parseOr(); err != nil {
+			break
+		}
+		v, ok := constValue(lag.s)
+		if !ok || v < 0 || v != Decimal(math.Trunc(float64(v))) {
+			return n, p.errorf(pos, "the lag must be a constant integer >= 0")
+		}
+		if err = p.expect("]"); err != nil {
+			break
+		}
+		if v > 0 {
+			n.s = Chain(n.s, newLag(int(v)))
+		}
+	}
+	return n, err
+}
+
+func (p *exprParser) parsePrimary() (exprNode, error) {
+	tok := p.tok
+	switch tok.kind {
+	case tokNum:
+		return exprNode{Const(tok.num), tok.pos}, p.next()
+
+	case tokIdent:
+		if err := p.next(); err != nil {
+			return exprNode{}, err
+		}
+		if p.tok.kind == tokOp && p.tok.text == "(" {
+			return p.parseCall(tok)
+		}
+		if tok.text == "close" {
+			return exprNode{Price(), tok.pos}, nil
+		}
+		if f, ok := exprFields[tok.text]; ok {
+			return exprNode{OnCandles(Price(), f), tok.pos}, nil
+		}
+		if _, ok := exprFuncs[tok.text]; ok {
+			return exprNode{}, p.errorf(tok.pos, "%s is a function, expected %q after it", tok, "(")
+		}
+		return exprNode{}, p.errorf(tok.pos, "unknown field %s", tok)
+
+	case tokOp:
+		if tok.text == "(" {
+			if err := p.next(); err != nil {
+				return exprNode{}, err
+			}
+			n, err := p.parseOr()
+			if err != nil {
+				return n, err
+			}
+			n.pos = tok.pos
+			return n, p.expect(")")
+		}
+	}
+	return exprNode{}, p.errorf(tok.pos, "unexpected %s, expected a number, a field or a function", tok)
+}
+
+func (p *exprParser) parseCall(name exprToken) (exprNode, error) {
+	if err := p.next(); err != nil { // (
+		return exprNode{}, err
+	}
+	var args []exprNode
+	if ok, err := p.accept(")"); err != nil {
+		return exprNode{}, err
+	} else if !ok {
+		for {
+			arg, err := p.parseOr()
+			if err != nil {
+				return arg, err
+			}
+			args = append(args, arg)
+			if ok, err = p.accept(","); err != nil {
+				return arg, err
+			} else if !ok {
+				break
+			}
+		}
+		if err := p.expect(")"); err != nil {
+			return exprNode{}, err
+		}
+	}
+
+	switch fn := name.text; fn {
+	case "crossover", "crossunder":
+		if len(args) != 2 {
+			return exprNode{}, p.errorf(name.pos, "%s expects %s (a, b), got %d", fn, plural(2, "argument"), len(args))
+		}
+		return exprNode{Chain(Sub(args[0].s, args[1].s), &cross{under: fn == "crossunder"}), name.pos}, nil
+	case "abs":
+		if len(args) != 1 {
+			return exprNode{}, p.errorf(name.pos, "abs expects %s (x), got %d", plural(1, "argument"), len(args))
+		}
+		return exprNode{Combine(func(vs ...Decimal) Decimal { return vs[0].Abs() }, args[0].s), name.pos}, nil
+	}
+
+	f, ok := exprFuncs[name.text]
+	if !ok {
+		if _, ok := exprFields[name.text]; ok || name.text == "close" {
+			return exprNode{}, p.errorf(name.pos, "%s is a field, not a function", name)
+		}
+		return exprNode{}, p.errorf(name.pos, "unknown function %s", name)
+	}
+
+	names := make([]string, 0, len(f.args)+1)
+	if f.src {
+		names = append(names, "input")
+	}
+	for _, a := range f.args {
+		names = append(names, a.name)
+	}
+	if len(args) != len(names) {
+		return exprNode{}, p.errorf(name.pos, "%s expects %s (%s), got %d", name.text, plural(len(names), "argument"), strings.Join(names, ", "), len(args))
+	}
+
+	var src Study
+	if f.src {
+		src, args = args[0].s, args[1:]
+	}
+	params := make([]Decimal, len(args))
+	for i, a := range args {
+		v, ok := constValue(a.s)
+		switch {
+		case !ok:
+			return exprNode{}, p.errorf(a.pos, "%s: %s must be a constant", name.text, f.args[i].name)
+		case !f.args[i].float && v != Decimal(math.Trunc(float64(v))):
+			return exprNode{}, p.errorf(a.pos, "%s: %s must be an integer, got %v", name.text, f.args[i].name, v)
+		}
+		params[i] = v
+	}
+
+	s, err := newExprStudy(f, params)
+	if err != nil {
+		return exprNode{}, p.errorf(name.pos, "%s: %v", name.text, err)
+	}
+	if src != nil {
+		s = Chain(src, s)
+	}
+	return exprNode{s, name.pos}, nil
+}
+
+// newExprStudy calls f.fn and returns the panics of the constructors, like invalid periods, as errors
+func newExprStudy(f exprFunc, params []Decimal) (s Study, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%v", r)
+		}
+	}()
+	return f.fn(params), nil
+}
+
+func contains(ss []string, s string) bool {
+	for _, v := range ss {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+func plural(n int, s string) string {
+	if n != 1 {
+		s += "s"
+	}
+	return strconv.Itoa(n) + " " + s
+}
+
+func isCmp(op string) bool { return contains([]string{"<", "<=", ">", ">=", "==", "!="}, op) }
+
+// constValue returns the value of s if it's a Const
+func constValue(s Study) (Decimal, bool) {
+	if c, ok := s.(*constant); ok && !c.price {
+		return c.v, true
+	}
+	return 0, false
+}
+
+func boolValue(b bool) Decimal {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// binaryOp returns a study that applies op to the values of a and b, constants are folded
+func binaryOp(op string, a, b Study) Study {
+	var fn func(a, b Decimal) Decimal
+	switch op {
+	case "+":
+		fn = func(a, b Decimal) Decimal { return a + b }
+	case "-":
+		fn = func(a, b Decimal) Decimal { return a - b }
+	case "*":
+		fn = func(a, b Decimal) Decimal { return a * b }
+	case "/":
+		fn = func(a, b Decimal) Decimal {
+			if isZero(b) {
+				return 0
+			}
+			return a / b
+		}
+	case "<":
+		fn = func(a, b Decimal) Decimal { return boolValue(a < b) }
+	case "<=":
+		fn = func(a, b Decimal) Decimal { return boolValue(a <= b) }
+	case ">":
+		fn = func(a, b Decimal) Decimal { return boolValue(a > b) }
+	case ">=":
+		fn = func(a, b Decimal) Decimal { return boolValue(a >= b) }
+	case "==":
+		fn = func(a, b Decimal) Decimal { return boolValue(a == b) }
+	case "!=":
+		fn = func(a, b Decimal) Decimal { return boolValue(a != b) }
+	case "&&":
+		fn = func(a, b Decimal) Decimal { return boolValue(a != 0 && b != 0) }
+	case "||":
+		fn = func(a, b Decimal) Decimal { return boolValue(a != 0 || b != 0) }
+	default:
+		panic("expr: unknown operator " + op)
+	}
+
+	av, aok := constValue(a)
+	bv, bok := constValue(b)
+	if aok && bok {
+		return Const(fn(av, bv))
+	}
+	return Combine(func(vs ...Decimal) Decimal { return fn(vs[0], vs[1]) }, a, b)
+}
+
+// unaryOp returns a study that applies op to the value of s, constants are folded
+func unaryOp(op string, s Study) Study {
+	fn := func(v Decimal) Decimal { return -v }
+	if op == "!" {
+		fn = func(v Decimal) Decimal { return boolValue(v == 0) }
+	}
+	if v, ok := constValue(s); ok {
+		return Const(fn(v))
+	}
+	return Combine(func(vs ...Decimal) Decimal { return fn(vs[0]) }, s)
+}
+
+func newLag(n int) *lag {
+	return &lag{data: NewCapped(n), n: n}
+}
+
+var _ Study = (*lag)(nil)
+
+// lag returns its input from n updates ago, 0 until it has n updates
+type lag struct {
+	noMulti
+	data *TA
+	n    int
+}
+
+func (s *lag) Update(vs ...Decimal) Decimal { return s.data.Update(vs[0]) }
+func (s *lag) Len() int                     { return s.n }
+func (s *lag) Lookback() int                { return s.n }
+func (s *lag) Clone() Study                 { return clone(s) }
+func (s *lag) Reset()                       { *s = *newLag(s.n) }
+
+var _ Study = (*cross)(nil)
+
+// cross returns 1 when its input crosses over 0, or under it if under is set, the first update always returns 0
+type cross struct {
+	noMulti
+	prev  Decimal
+	set   bool
+	under bool
+}
+
+func (s *cross) Update(vs ...Decimal) Decimal {
+	v := vs[0]
+	ok := s.set && (!s.under && decimal.Crosover(v, s.prev, 0) || s.under && decimal.Crossunder(v, s.prev, 0))
+	s.prev, s.set = v, true
+	return boolValue(ok)
+}
+
+func (s *cross) Len() int      { return 0 }
+func (s *cross) Lookback() int { return 1 }
+func (s *cross) Clone() Study  { return clone(s) }
+func (s *cross) Reset()        { *s = cross{under: s.under} }
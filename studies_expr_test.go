@@ -0,0 +1,168 @@
+package ta
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"go.oneofone.dev/ta/decimal"
+)
+
+func TestParseExpr(t *testing.T) {
+	candles := testCandles()
+
+	cross := MustParseExpr("crossover(ema(close, 9), ema(close, 21))")
+	var (
+		ema9, ema21 = EMA(9), EMA(21)
+		prev        Decimal
+		n           int
+	)
+	for i, c := range candles {
+		d := ema9.Update(c.Close) - ema21.Update(c.Close)
+		exp := boolValue(i > 0 && decimal.Crosover(d, prev, 0))
+		if got := cross.UpdateCandle(c)[0]; got != exp {
+			t.Fatalf("[%d] expected %v, got %v", i, exp, got)
+		}
+		prev = d
+		n += int(exp)
+	}
+	if n == 0 {
+		t.Fatal("expected at least one crossover")
+	}
+	if lb := Lookback(cross); lb != 21 {
+		t.Fatalf("expected lookback 21, got %d", lb)
+	}
+
+	for _, c := range []struct {
+		expr string
+		fn   func(i int, c *Candle) Decimal
+	}{
+		{"close - 2 * sma(close, 20)", func() func(int, *Candle) Decimal {
+			sma := SMA(20)
+			return func(_ int, c *Candle) Decimal { return c.Close - 2*sma.Update(c.Close) }
+		}()},
+		{"(high + low) / 2 == hl2 and (-volume < 0) == (volume > 0)", func(int, *Candle) Decimal { return 1 }},
+		{"close[2] + ema(high, 5)[1]", func() func(int, *Candle) Decimal {
+			ema := EMA(5)
+			var closes, emas []Decimal
+			return func(i int, c *Candle) (v Decimal) {
+				closes, emas = append(closes, c.Close), append(emas, ema.Update(c.High))
+				if i >= 2 {
+					v += closes[i-2]
+				}
+				if i >= 1 {
+					v += emas[i-1]
+				}
+				return v
+			}
+		}()},
+		{"ATR(14) / close * 100", func() func(int, *Candle) Decimal {
+			natr := NATR(14)
+			return func(_ int, c *Candle) Decimal { return natr.UpdateCandle(c)[0] }
+		}()},
+		{"macdsignal(close, 12, 26, 9) - bblower(hlc3, 20, 2.5)", func() func(int, *Candle) Decimal {
+			macd, bb := MACD(12, 26, 9), BBandsLimits(20, 2.5, 2.5)
+			return func(_ int, c *Candle) Decimal { return macd.UpdateAll(c.Close)[1] - bb.UpdateAll(c.HLC3())[2] }
+		}()},
+		{"rsi(close, 14) > 70 || rsi(close, 14) < 30 && !(close < open)", func() func(int, *Candle) Decimal {
+			rsi := RSI(14)
+			return func(_ int, c *Candle) Decimal {
+				v := rsi.Update(c.Close)
+				return boolValue(v > 70 || v < 30 && c.Close >= c.Open)
+			}
+		}()},
+		{"abs(mom(close, 3)) / 0", func(int, *Candle) Decimal { return 0 }},
+	} {
+		s, err := ParseExpr(c.expr)
+		if err != nil {
+			t.Fatalf("%s: %v", c.expr, err)
+		}
+		for i, cd := range candles {
+			exp, got := c.fn(i, cd), s.UpdateCandle(cd)[0]
+			if !decimal.EqualApprox(exp.Float(), got.Float(), 1e-9) {
+				t.Fatalf("%s [%d]: expected %v, got %v", c.expr, i, exp, got)
+			}
+		}
+	}
+
+	// constants are folded
+	if v, ok := constValue(MustParseExpr("-2 * 3 + 1 > -6")); !ok || v != 1 {
+		t.Fatalf("expected a constant 1, got %v", v)
+	}
+
+	// [open, high, low, close, volume] values work the same as candles
+	s, exp := MustParseExpr("hl2 - close[1] + obv()"), MustParseExpr("hl2 - close[1] + obv()")
+	for i, c := range candles {
+		if got, exp := s.Update(c.Values()...), exp.UpdateCandle(c)[0]; got != exp {
+			t.Fatalf("[%d] expected %v, got %v", i, exp, got)
+		}
+	}
+}
+
+func TestParseExprErrors(t *testing.T) {
+	for _, c := range []struct {
+		expr string
+		pos  int
+		msg  string
+	}{
+		{"", 0, "unexpected end of expression"},
+		{"close + ", 8, "unexpected end of expression"},
+		{"close)", 5, `unexpected ")"`},
+		{"close = open", 6, `did you mean "=="`},
+		{"close $ 1", 6, "unexpected character '$'"},
+		{"1.2.3", 0, `invalid number "1.2.3"`},
+		{"foo * 2", 0, `unknown field "foo"`},
+		{"2 * foo(close)", 4, `unknown function "foo"`},
+		{"close(1)", 0, `"close" is a field`},
+		{"ema + 1", 0, `"ema" is a function`},
+		{"ema(close)", 0, "ema expects 2 arguments (input, period), got 1"},
+		{"atr(close, 14)", 0, "atr expects 1 argument (period), got 2"},
+		{"ema(close, 9", 12, `expected ")"`},
+		{"ema(close, 9.5)", 11, "ema: period must be an integer, got 9.5"},
+		{"ema(close, high)", 11, "ema: period must be a constant"},
+		{"sma(close, 1)", 0, "sma: period < 2"},
+		{"close[-1]", 6, "lag must be a constant integer"},
+		{"close[1", 7, `expected "]"`},
+		{"1 < close < 2", 10, "comparisons can't be chained"},
+		{"crossover(close)", 0, "crossover expects 2 arguments"},
+		{"trange(1)", 0, "trange expects 0 arguments (), got 1"},
+	} {
+		_, err := ParseExpr(c.expr)
+		var e *ExprError
+		if !errors.As(err, &e) {
+			t.Fatalf("%q: expected an *ExprError, got %v", c.expr, err)
+		}
+		if e.Pos != c.pos || !strings.Contains(e.Msg, c.msg) || e.Expr != c.expr {
+			t.Fatalf("%q: expected %q at %d, got %q at %d", c.expr, c.msg, c.pos, e.Msg, e.Pos)
+		}
+	}
+
+	_, err := ParseExpr("ema(close, 9) > emaa(close, 21)")
+	if exp := `ta: invalid expression at column 17: unknown function "emaa"`; err == nil || err.Error() != exp {
+		t.Fatalf("expected %q, got %v", exp, err)
+	}
+}
+
+func TestStreamFromCandleStudy(t *testing.T) {
+	const expr = "crossunder(close, sma(close, 10))"
+	var (
+		candles = testCandles()
+		exp     = ApplyCandleSlice(MustParseExpr(expr), candles)[0]
+		st      = StreamFromCandleStudy(MustParseExpr(expr), 1, true)
+		done    = make(chan *TA)
+	)
+	go func() {
+		out := NewSize(len(candles), true)
+		for v := range st.Chan() {
+			out.Append(v)
+		}
+		done <- out
+	}()
+	for _, c := range candles {
+		st.Update(c)
+	}
+	st.Close()
+	if got := <-done; !got.Equal(exp) {
+		t.Fatalf("expected %v, got %v", exp.Floats(), got.Floats())
+	}
+}
@@ -0,0 +1,378 @@
+package ta
+
+import "math"
+
+// the hilbert transform helpers are a port of TA-Lib's ta_utility.h macros,
+// the odd/even split and the weird ordering is intentional to keep the results identical.
+
+const (
+	htA Decimal = 0.0962
+	htB Decimal = 0.5769
+
+	rad2Deg Decimal = 180 / math.Pi
+	deg2Rad Decimal = math.Pi / 180
+
+	deg2RadBy360 Decimal = 2 * math.Pi
+)
+
+// htPrice is the 4 bar weighted moving average used to smooth the price before the transform
+type htPrice struct {
+	hist     [4]Decimal
+	sub      Decimal
+	sum      Decimal
+	trailing Decimal
+	n        int
+}
+
+// update returns the smoothed price, ok is false for the first 3 values
+func (p *htPrice) update(v Decimal) (_ Decimal, ok bool) {
+	n := p.n
+	p.n++
+	if n < 3 {
+		p.sub += v
+		p.sum += v * Decimal(n+1)
+		p.hist[n] = v
+		return 0, false
+	}
+
+	p.sub += v
+	p.sub -= p.trailing
+	p.sum += v * 4
+	p.trailing = p.hist[(n-3)%4]
+	p.hist[n%4] = v
+	sm := p.sum * 0.1
+	p.sum -= p.sub
+	return sm, true
+}
+
+type hilbert struct {
+	odd, even             [3]Decimal
+	prevOdd, prevEven     Decimal
+	prevInOdd, prevInEven Decimal
+}
+
+func (h *hilbert) transform(in Decimal, idx int, even bool, adj Decimal) (v Decimal) {
+	t := htA * in
+	if even {
+		v = -h.even[idx]
+		h.even[idx] = t
+		v += t
+		v -= h.prevEven
+		h.prevEven = htB * h.prevInEven
+		v += h.prevEven
+		h.prevInEven = in
+	} else {
+		v = -h.odd[idx]
+		h.odd[idx] = t
+		v += t
+		v -= h.prevOdd
+		h.prevOdd = htB * h.prevInOdd
+		v += h.prevOdd
+		h.prevInOdd = in
+	}
+	return v * adj
+}
+
+// htCore holds the shared state of the hilbert transform based studies (MAMA, HT_*)
+type htCore struct {
+	detrender, q1, ji, jq hilbert
+
+	i1OddPrev2, i1OddPrev3   Decimal
+	i1EvenPrev2, i1EvenPrev3 Decimal
+
+	i2, q2         Decimal
+	prevI2, prevQ2 Decimal
+	re, im         Decimal
+	period         Decimal
+
+	// inPhase and quadrature of the last step
+	inPhase, quadrature Decimal
+
+	idx int
+}
+
+// step runs the transform on the smoothed price, even is the parity of the bar's index
+func (h *htCore) step(price Decimal, even bool) {
+	adj := 0.075*h.period + 0.54
+
+	i1 := h.i1OddPrev3
+	if even {
+		i1 = h.i1EvenPrev3
+	}
+
+	detrender := h.detrender.transform(price, h.idx, even, adj)
+	q1 := h.q1.transform(detrender, h.idx, even, adj)
+	ji := h.ji.transform(i1, h.idx, even, adj)
+	jq := h.jq.transform(q1, h.idx, even, adj)
+
+	if even {
+		if h.idx++; h.idx == 3 {
+			h.idx = 0
+		}
+		h.i1OddPrev3, h.i1OddPrev2 = h.i1OddPrev2, detrender
+	} else {
+		h.i1EvenPrev3, h.i1EvenPrev2 = h.i1EvenPrev2, detrender
+	}
+
+	h.q2 = 0.2*(q1+ji) + 0.8*h.prevQ2
+	h.i2 = 0.2*(i1-jq) + 0.8*h.prevI2
+	h.inPhase, h.quadrature = i1, q1
+}
+
+// updatePeriod computes the dominant cycle period for the next bar
+func (h *htCore) updatePeriod() {
+	h.re = 0.2*(h.i2*h.prevI2+h.q2*h.prevQ2) + 0.8*h.re
+	h.im = 0.2*(h.i2*h.prevQ2-h.q2*h.prevI2) + 0.8*h.im
+	h.prevQ2, h.prevI2 = h.q2, h.i2
+
+	prev := h.period
+	if h.im != 0 && h.re != 0 {
+		h.period = 360 / ((h.im / h.re).Atan() * rad2Deg)
+	}
+	if max := 1.5 * prev; h.period > max {
+		h.period = max
+	}
+	if min := 0.67 * prev; h.period < min {
+		h.period = min
+	}
+	if h.period < 6 {
+		h.period = 6
+	} else if h.period > 50 {
+		h.period = 50
+	}
+	h.period = 0.2*h.period + 0.8*prev
+}
+
+const (
+	htDCPeriod uint8 = iota
+	htPhasor
+	htDCPhase
+	htSine
+	htTrendline
+	htTrendMode
+)
+
+// HTDCPeriod - Hilbert Transform - Dominant Cycle Period
+func HTDCPeriod() Study {
+	return newHT(htDCPeriod)
+}
+
+// HTDCPhase - Hilbert Transform - Dominant Cycle Phase
+func HTDCPhase() Study {
+	return newHT(htDCPhase)
+}
+
+// HTPhasor - Hilbert Transform - Phasor Components
+// Update returns the in-phase component
+// UpdateAll returns [inPhase, quadrature]
+func HTPhasor() MultiVarStudy {
+	return newHT(htPhasor)
+}
+
+// HTSine - Hilbert Transform - SineWave
+// Update returns the sine
+// UpdateAll returns [sine, leadSine]
+func HTSine() MultiVarStudy {
+	return newHT(htSine)
+}
+
+// HTTrendline - Hilbert Transform - Instantaneous Trendline
+func HTTrendline() Study {
+	return newHT(htTrendline)
+}
+
+// HTTrendMode - Hilbert Transform - Trend vs Cycle Mode, returns 1 for trend and 0 for cycle
+func HTTrendMode() Study {
+	return newHT(htTrendMode)
+}
+
+func newHT(mode uint8) *htStudy {
+	s := &htStudy{
+		mode:  mode,
+		start: 12,
+	}
+	if mode >= htDCPhase {
+		// these need a longer warm up, same as TA-Lib
+		s.start = 37
+		s.smooth = NewCapped(50)
+		s.raw = NewCapped(50)
+	}
+	return s
+}
+
+var _ MultiVarStudy = (*htStudy)(nil)
+
+// htStudy implements all the HT_* functions, the values match TA-Lib after
+// the first 32 (HT_DCPERIOD, HT_PHASOR) or 63 (the rest) values.
+type htStudy struct {
+	price htPrice
+	ht    htCore
+
+	smooth *TA // smoothed prices
+	raw    *TA // raw prices, used for the trendline
+
+	smoothPeriod Decimal
+
+	dcPhase      Decimal
+	prevDCPhase  Decimal
+	sine         Decimal
+	leadSine     Decimal
+	prevSine     Decimal
+	prevLeadSine Decimal
+
+	iTrend1, iTrend2, iTrend3 Decimal
+
+	trendline   Decimal
+	trend       Decimal
+	daysInTrend int
+
+	start int
+	idx   int
+	mode  uint8
+}
+
+func (s *htStudy) Update(vs ...Decimal) Decimal {
+	return s.UpdateAll(vs...)[0]
+}
+
+func (s *htStudy) UpdateAll(vs ...Decimal) []Decimal {
+	for _, v := range vs {
+		s.update(v)
+	}
+
+	switch s.mode {
+	case htPhasor:
+		return []Decimal{s.ht.inPhase, s.ht.quadrature}
+	case htDCPhase:
+		return []Decimal{s.dcPhase}
+	case htSine:
+		return []Decimal{s.sine, s.leadSine}
+	case htTrendline:
+		return []Decimal{s.trendline}
+	case htTrendMode:
+		return []Decimal{s.trend}
+	default:
+		return []Decimal{s.smoothPeriod}
+	}
+}
+
+func (s *htStudy) update(v Decimal) {
+	idx := s.idx
+	s.idx++
+
+	if s.raw != nil {
+		s.raw.Update(v)
+	}
+
+	sm, ok := s.price.update(v)
+	if !ok || idx < s.start {
+		return
+	}
+
+	ht := &s.ht
+	ht.step(sm, idx%2 == 0)
+	ht.updatePeriod()
+	s.smoothPeriod = 0.33*ht.period + 0.67*s.smoothPeriod
+
+	if s.mode < htDCPhase {
+		return
+	}
+
+	s.smooth.Update(sm)
+	n := int(s.smoothPeriod + 0.5)
+
+	if s.mode != htTrendline {
+		s.updatePhase(n)
+	}
+
+	if s.mode != htDCPhase && s.mode != htSine {
+		var avg Decimal
+		for i := 0; i < n; i++ {
+			avg += s.raw.Get(-1 - i)
+		}
+		if n > 0 {
+			avg /= Decimal(n)
+		}
+		s.trendline = (4*avg + 3*s.iTrend1 + 2*s.iTrend2 + s.iTrend3) / 10
+		s.iTrend3, s.iTrend2, s.iTrend1 = s.iTrend2, s.iTrend1, avg
+	}
+
+	if s.mode == htTrendMode {
+		s.updateTrend(sm)
+	}
+}
+
+func (s *htStudy) updatePhase(n int) {
+	var re, im Decimal
+	for i := 0; i < n; i++ {
+		a := Decimal(i) * deg2RadBy360 / Decimal(n)
+		p := s.smooth.Get(-1 - i)
+		re += a.Sin() * p
+		im += a.Cos() * p
+	}
+
+	s.prevDCPhase = s.dcPhase
+	if abs := im.Abs(); abs > 0 {
+		s.dcPhase = (re / im).Atan() * rad2Deg
+	} else if abs <= 0.01 {
+		if re < 0 {
+			s.dcPhase -= 90
+		} else if re > 0 {
+			s.dcPhase += 90
+		}
+	}
+
+	s.dcPhase += 90
+	s.dcPhase += 360 / s.smoothPeriod
+	if im < 0 {
+		s.dcPhase += 180
+	}
+	if s.dcPhase > 315 {
+		s.dcPhase -= 360
+	}
+
+	s.prevSine, s.prevLeadSine = s.sine, s.leadSine
+	s.sine = (s.dcPhase * deg2Rad).Sin()
+	s.leadSine = ((s.dcPhase + 45) * deg2Rad).Sin()
+}
+
+func (s *htStudy) updateTrend(sm Decimal) {
+	s.trend = 1
+	if (s.sine > s.leadSine && s.prevSine <= s.prevLeadSine) || (s.sine < s.leadSine && s.prevSine >= s.prevLeadSine) {
+		s.daysInTrend = 0
+		s.trend = 0
+	}
+
+	if s.daysInTrend++; Decimal(s.daysInTrend) < 0.5*s.smoothPeriod {
+		s.trend = 0
+	}
+
+	if d := s.dcPhase - s.prevDCPhase; s.smoothPeriod != 0 && d > 0.67*360/s.smoothPeriod && d < 1.5*360/s.smoothPeriod {
+		s.trend = 0
+	}
+
+	if s.trendline != 0 && ((sm-s.trendline)/s.trendline).Abs() >= 0.015 {
+		s.trend = 1
+	}
+}
+
+func (s *htStudy) Len() int { return 0 }
+
+// Lookback returns the number of values before the output matches TA-Lib
+func (s *htStudy) Lookback() int {
+	if s.start == 12 {
+		return 32
+	}
+	return 63
+}
+func (s *htStudy) Clone() Study { return clone(s) }
+func (s *htStudy) Reset()       { *s = *newHT(s.mode) }
+func (s *htStudy) LenAll() []int {
+	if s.mode == htPhasor || s.mode == htSine {
+		return []int{0, 0}
+	}
+	return []int{0}
+}
+
+func (s *htStudy) ToStudy() (Study, bool)         { return s, true }
+func (s *htStudy) ToMulti() (MultiVarStudy, bool) { return s, true }
@@ -0,0 +1,116 @@
+package ta
+
+import "go.oneofone.dev/ta/decimal"
+
+// Ichimoku output indices
+const (
+	IchimokuTenkan = iota
+	IchimokuKijun
+	IchimokuSenkouA
+	IchimokuSenkouB
+	IchimokuChikou
+)
+
+// IchimokuStudy is returned by Ichimoku
+type IchimokuStudy interface {
+	DisplacedStudy
+	CandleStudy
+
+	// Cloud returns [senkou A, senkou B] for the current bar, the values calculated displacement bars ago,
+	// both are 0 until there are enough bars.
+	Cloud() (a, b Decimal)
+}
+
+// Ichimoku - Ichimoku Kinko Hyo, a period of 0 uses the default (9, 26 and 52),
+// the displacement of the cloud and chikou is the kijun period.
+// UpdateAll returns the values calculated on the current bar, [tenkan, kijun, senkou A, senkou B, chikou],
+// senkou A and B belong to kijun bars ahead and chikou (the close) to kijun bars behind, see Displacement.
+// ApplyMultiVarStudy and ApplyCandles realign the outputs to the input bars.
+// Update returns tenkan
+func Ichimoku(tenkan, kijun, senkouB int) IchimokuStudy {
+	if tenkan == 0 {
+		tenkan = 9
+	}
+	if kijun == 0 {
+		kijun = 26
+	}
+	if senkouB == 0 {
+		senkouB = 52
+	}
+	checkPeriod(tenkan, 1)
+	checkPeriod(kijun, 1)
+	checkPeriod(senkouB, 1)
+
+	return &ichimoku{
+		tenkan:  newHighLow(tenkan),
+		kijun:   newHighLow(kijun),
+		senkouB: newHighLow(senkouB),
+		spanA:   NewCapped(kijun),
+		spanB:   NewCapped(kijun),
+		disp:    kijun,
+	}
+}
+
+var _ IchimokuStudy = (*ichimoku)(nil)
+
+type ichimoku struct {
+	tenkan, kijun, senkouB highLow
+
+	// the senkou values of the last disp bars, the oldest one is the current cloud
+	spanA, spanB *TA
+	a, b         Decimal
+
+	disp int
+}
+
+func (s *ichimoku) Update(vs ...Decimal) Decimal      { return s.UpdateAll(vs...)[IchimokuTenkan] }
+func (s *ichimoku) UpdateAll(vs ...Decimal) []Decimal { return s.update(barFromValues(vs)) }
+func (s *ichimoku) UpdateCandle(c *Candle) []Decimal  { return s.update(barFromCandle(c)) }
+
+func (s *ichimoku) update(b bar) []Decimal {
+	var (
+		tenkan  = s.tenkan.update(b)
+		kijun   = s.kijun.update(b)
+		senkouA = (tenkan + kijun) / 2
+		senkouB = s.senkouB.update(b)
+	)
+	s.a, s.b = s.spanA.Update(senkouA), s.spanB.Update(senkouB)
+	return []Decimal{tenkan, kijun, senkouA, senkouB, b.close}
+}
+
+func (s *ichimoku) Cloud() (a, b Decimal) { return s.a, s.b }
+
+func (s *ichimoku) Displacement() []int {
+	return []int{0, 0, s.disp, s.disp, -s.disp}
+}
+
+func (s *ichimoku) Len() int      { return 0 }
+func (s *ichimoku) LenAll() []int { return []int{0, 0, 0, 0, 0} }
+
+func (s *ichimoku) Lookback() int {
+	return decimal.Max(s.tenkan.high.Cap(), s.kijun.high.Cap(), s.senkouB.high.Cap()) - 1
+}
+
+func (s *ichimoku) Clone() Study { return clone(s) }
+
+func (s *ichimoku) Reset() {
+	*s = *Ichimoku(s.tenkan.high.Cap(), s.kijun.high.Cap(), s.senkouB.high.Cap()).(*ichimoku)
+}
+
+func (s *ichimoku) ToStudy() (Study, bool)         { return s, true }
+func (s *ichimoku) ToMulti() (MultiVarStudy, bool) { return s, true }
+
+// highLow is the mid point of the highest high and lowest low of the last period bars
+type highLow struct {
+	high, low *TA
+}
+
+func newHighLow(period int) highLow {
+	return highLow{high: newRing(period), low: newRing(period)}
+}
+
+func (hl highLow) update(b bar) Decimal {
+	hl.high.Update(b.high)
+	hl.low.Update(b.low)
+	return (hl.high.Max() + hl.low.Min()) / 2
+}
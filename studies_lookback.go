@@ -0,0 +1,205 @@
+package ta
+
+import (
+	"math"
+	"time"
+)
+
+// LookbackStudy is a study that knows how much data it needs before its output is valid,
+// all the studies in this package implement it.
+type LookbackStudy interface {
+	Study
+
+	// Lookback returns the number of updates before the first valid output, same as TA-Lib's lookback,
+	// so the output of update Lookback() + 1 is the first one that can be used.
+	// Every update is a single input, for example a price, a candle or a [volume, price] pair.
+	Lookback() int
+}
+
+// Lookback returns s.Lookback() if s implements LookbackStudy, 0 otherwise
+func Lookback(s Study) int {
+	if ls, ok := s.(LookbackStudy); ok {
+		return ls.Lookback()
+	}
+	return 0
+}
+
+// readier is implemented by studies that can't tell if they're ready by the number of updates alone,
+// for example the ones that depend on timestamps
+type readier interface {
+	ready() bool
+}
+
+// ReadyStudy is returned by Readiness
+type ReadyStudy interface {
+	TimedStudy
+
+	// Lookback returns the lookback of the wrapped study
+	Lookback() int
+
+	// Ready returns true once the output of the wrapped study is valid
+	Ready() bool
+
+	// Count returns the number of updates so far
+	Count() int
+}
+
+// Readiness returns a wrapper around s that tracks whether its output is valid,
+// every Update* call is counted as a single update and the study is ready once it had Lookback(s) + 1 updates.
+// Studies that depend on timestamps, like Pivots and MultiTimeframeAt, are ready once they have a full session or bar.
+// UpdateCandle and UpdateCandleAt feed the close to studies that don't support candles.
+func Readiness(s Study) ReadyStudy {
+	t := &tracked{s: s, lb: Lookback(s)}
+	t.m, _ = s.ToMulti()
+	return t
+}
+
+var _ ReadyStudy = (*tracked)(nil)
+
+type tracked struct {
+	s     Study
+	m     MultiVarStudy
+	lb    int
+	count int
+}
+
+func (s *tracked) Update(vs ...Decimal) Decimal {
+	s.count++
+	return s.s.Update(vs...)
+}
+
+func (s *tracked) UpdateAll(vs ...Decimal) []Decimal {
+	s.count++
+	if s.m != nil {
+		return s.m.UpdateAll(vs...)
+	}
+	return []Decimal{s.s.Update(vs...)}
+}
+
+func (s *tracked) UpdateCandle(c *Candle) []Decimal {
+	s.count++
+	return updateWithBar(s.s, barFromCandle(c))
+}
+
+func (s *tracked) UpdateCandleAt(ts time.Time, c *Candle) []Decimal {
+	if t, ok := s.s.(TimedStudy); ok {
+		s.count++
+		return t.UpdateCandleAt(ts, c)
+	}
+	return s.UpdateCandle(c)
+}
+
+func (s *tracked) Ready() bool {
+	if r, ok := s.s.(readier); ok {
+		return r.ready()
+	}
+	return s.count > s.lb
+}
+
+func (s *tracked) Count() int    { return s.count }
+func (s *tracked) Lookback() int { return s.lb }
+
+func (s *tracked) Clone() Study { return clone(s) }
+
+func (s *tracked) Reset() {
+	s.s.Reset()
+	s.count = 0
+}
+
+func (s *tracked) Len() int { return s.s.Len() }
+
+func (s *tracked) LenAll() []int {
+	if s.m != nil {
+		return s.m.LenAll()
+	}
+	return []int{s.s.Len()}
+}
+
+func (s *tracked) ToStudy() (Study, bool)         { return s, true }
+func (s *tracked) ToMulti() (MultiVarStudy, bool) { return s, true }
+
+// Warmup controls what happens to the values a study returns before it's ready
+type Warmup uint8
+
+const (
+	// WarmupKeep keeps all the values
+	WarmupKeep Warmup = iota
+
+	// WarmupTrim drops the first Lookback(s) values, same as TA-Lib
+	WarmupTrim
+
+	// WarmupNaN replaces the first Lookback(s) values with NaN
+	WarmupNaN
+)
+
+// ApplyStudyWarmup applies the given study to the input(s) and returns all the results, unlike ApplyStudy,
+// the length only depends on the inputs and w:
+// WarmupKeep and WarmupNaN return a value for every input, WarmupTrim returns Lookback(s) values less.
+func ApplyStudyWarmup(s Study, w Warmup, tas ...*TA) *TA {
+	ln := tas[0].Len()
+	out := NewSize(ln, true)
+	vals := make([]Decimal, len(tas))
+
+	for i := 0; i < ln; i++ {
+		for j := 0; j < len(tas); j++ {
+			vals[j] = tas[j].Get(i)
+		}
+		out.Append(s.Update(vals...))
+	}
+
+	return warmup(out, Lookback(s), w)
+}
+
+// ApplyMultiVarStudyWarmup same as ApplyStudyWarmup for all the outputs of s,
+// the outputs of a DisplacedStudy are shifted after the warm up values are handled.
+func ApplyMultiVarStudyWarmup(s MultiVarStudy, w Warmup, tas ...*TA) []*TA {
+	ln := tas[0].Len()
+	vals := make([]Decimal, len(tas))
+	var out []*TA
+
+	for i := 0; i < ln; i++ {
+		for j := 0; j < len(tas); j++ {
+			vals[j] = tas[j].Get(i)
+		}
+		vs := s.UpdateAll(vals...)
+		if out == nil {
+			out = make([]*TA, len(vs))
+			for j := range out {
+				out[j] = NewSize(ln, true)
+			}
+		}
+		for j, v := range vs {
+			out[j].Append(v)
+		}
+	}
+
+	if out == nil {
+		out = make([]*TA, len(s.LenAll()))
+		for j := range out {
+			out[j] = NewSize(0, true)
+		}
+	}
+
+	lb := Lookback(s)
+	for j, ta := range out {
+		out[j] = warmup(ta, lb, w)
+	}
+	return displace(s, out)
+}
+
+// warmup applies w to the first lb values of ta
+func warmup(ta *TA, lb int, w Warmup) *TA {
+	if lb > ta.Len() {
+		lb = ta.Len()
+	}
+	switch w {
+	case WarmupTrim:
+		return NewSize(ta.Len()-lb, true).Append(ta.v[lb:]...)
+	case WarmupNaN:
+		nan := Decimal(math.NaN())
+		for i := 0; i < lb; i++ {
+			ta.v[i] = nan
+		}
+	}
+	return ta
+}
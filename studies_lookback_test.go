@@ -0,0 +1,202 @@
+package ta
+
+import (
+	"testing"
+	"time"
+
+	"go.oneofone.dev/ta/decimal"
+)
+
+func TestLookback(t *testing.T) {
+	for name, fn := range stateStudies() {
+		if _, ok := fn().(LookbackStudy); !ok {
+			t.Errorf("%s doesn't implement LookbackStudy", name)
+		}
+	}
+
+	// same as TA-Lib
+	for _, c := range []struct {
+		name string
+		s    Study
+		lb   int
+	}{
+		{"SMA", SMA(30), 29},
+		{"EMA", EMA(30), 29},
+		{"WMA", WMA(30), 29},
+		{"DEMA", DEMA(30), 58},
+		{"TEMA", TEMA(30), 87},
+		{"TRIMA", TRIMA(30), 29},
+		{"KAMA", KAMA(30), 30},
+		{"T3", T3(5), 24},
+		{"MAMA", MAMA(30), 32},
+		{"TRIX", TRIX(30), 88},
+		{"RSI", RSI(14), 14},
+		{"CMO", CMO(14), 14},
+		{"MACD", MACD(12, 26, 9), 33},
+		{"BBands", BBands(20), 19},
+		{"MinMax", MinMax(30), 29},
+		{"MOM", MOM(10), 10},
+		{"PPO", PPO(12, 26, EMA), 25},
+		{"BOP", BOP(), 0},
+		{"StochSlow", StochSlow(5, 3, 3, SMA), 8},
+		{"StochFast", StochFast(5, 3, SMA), 6},
+		{"StochRSI", StochRSI(14, 5, 3, SMA), 20},
+		{"WilliamsR", WilliamsR(14), 13},
+		{"CCI", CCI(14), 13},
+		{"UltOsc", UltimateOscillator(7, 14, 28), 28},
+		{"TRange", TRange(), 1},
+		{"ATR", ATR(14), 14},
+		{"NATR", NATR(14), 14},
+		{"PlusDI", PlusDI(14), 14},
+		{"DX", DX(14), 14},
+		{"ADX", ADX(14), 27},
+		{"ADXR", ADXR(14), 40},
+		{"Aroon", Aroon(14), 14},
+		{"LinearReg", LinearReg(14), 13},
+		{"Beta", Beta(5), 5},
+		{"Correl", Correl(30), 29},
+		{"SAR", SAR(0.02, 0.2), 1},
+		{"OBV", OBV(), 0},
+		{"ADOSC", ADOSC(3, 10), 9},
+		{"MFI", MFI(14), 14},
+		{"HTDCPeriod", HTDCPeriod(), 32},
+		{"HTSine", HTSine(), 63},
+		{"Locked", LockedStudy(RSIExt(EMA(5))), 9},
+		{"OnCandles", OnCandles(SMA(5), FieldHigh), 4},
+		{"MTF", MultiTimeframe(func() Study { return SMA(3) }, 5, false), 14},
+	} {
+		if lb := Lookback(c.s); lb != c.lb {
+			t.Errorf("%s: expected %d, got %d", c.name, c.lb, lb)
+		}
+	}
+}
+
+// the output of fixed window studies only depends on the last Lookback() + 1 inputs
+func TestLookbackWindow(t *testing.T) {
+	candles := testCandles()
+	for name, fn := range map[string]func() Study{
+		"SMA":       func() Study { return SMA(10) },
+		"WMA":       func() Study { return WMA(10) },
+		"TRIMA":     func() Study { return TRIMA(10) },
+		"MinMax":    func() Study { return MinMax(10) },
+		"BBands":    func() Study { return BBands(10) },
+		"ROC":       func() Study { return ROC(10) },
+		"WilliamsR": func() Study { return WilliamsR(14) },
+		"CCI":       func() Study { return CCI(14) },
+		"Donchian":  func() Study { return DonchianChannels(20) },
+		"LinearReg": func() Study { return LinearReg(14) },
+		"Correl":    func() Study { return Correl(30) },
+		"Aroon":     func() Study { return Aroon(14) },
+		"UltOsc":    func() Study { return UltimateOscillator(7, 14, 28) },
+		"MFI":       func() Study { return MFI(14) },
+		"Patterns":  func() Study { return CandlePatterns(nil, AllCandlePatterns()...) },
+	} {
+		var (
+			full = fn()
+			lb   = Lookback(full)
+			exp  [][]Decimal
+		)
+		for _, c := range candles {
+			exp = append(exp, updateState(name, full, c))
+		}
+		for _, start := range []int{1, 17, 100} {
+			s := fn()
+			var got []Decimal
+			for _, c := range candles[start : start+lb+1] {
+				got = updateState(name, s, c)
+			}
+			for i, e := range exp[start+lb] {
+				if !decimal.EqualApprox(e.Float(), got[i].Float(), 1e-9) {
+					t.Fatalf("%s [%d]: expected %v, got %v", name, start, exp[start+lb], got)
+				}
+			}
+		}
+	}
+}
+
+func TestApplyStudyWarmup(t *testing.T) {
+	ln := testClose.Len()
+
+	keep := ApplyStudyWarmup(SMA(10), WarmupKeep, testClose)
+	trim := ApplyStudyWarmup(SMA(10), WarmupTrim, testClose)
+	nan := ApplyStudyWarmup(SMA(10), WarmupNaN, testClose)
+	if keep.Len() != ln || nan.Len() != ln || trim.Len() != ln-9 {
+		t.Fatalf("unexpected lengths %d %d %d", keep.Len(), trim.Len(), nan.Len())
+	}
+	for i := 0; i < ln; i++ {
+		v := nan.Get(i)
+		if i < 9 {
+			if !v.IsNaN() {
+				t.Fatalf("[%d] expected NaN, got %v", i, v)
+			}
+			continue
+		}
+		if v != keep.Get(i) || v != trim.Get(i-9) {
+			t.Fatalf("[%d] expected %v, got %v %v", i, keep.Get(i), v, trim.Get(i-9))
+		}
+	}
+	if exp := ApplyStudy(SMA(10), testClose); !trim.Slice(-10, 0).Equal(exp) {
+		t.Fatalf("expected %v, got %v", exp, trim.Slice(-10, 0))
+	}
+
+	if res := ApplyStudyWarmup(SMA(10), WarmupTrim, testClose.Slice(0, 5)); res.Len() != 0 {
+		t.Fatalf("expected an empty result, got %v", res)
+	}
+
+	macd := ApplyMultiVarStudyWarmup(MACD(12, 26, 9), WarmupTrim, testClose)
+	if len(macd) != 3 {
+		t.Fatalf("expected 3 outputs, got %d", len(macd))
+	}
+	for _, ta := range macd {
+		if ta.Len() != ln-33 {
+			t.Fatalf("expected %d values, got %d", ln-33, ta.Len())
+		}
+	}
+
+	// the warm up is handled before the outputs are displaced
+	ich := ApplyMultiVarStudyWarmup(Ichimoku(9, 26, 52), WarmupNaN, testHigh, testLow, testClose)
+	for i, n := range []int{ln, ln, ln + 26, ln + 26, ln} {
+		if ich[i].Len() != n {
+			t.Fatalf("[%d] expected %d values, got %d", i, n, ich[i].Len())
+		}
+	}
+	if v := ich[IchimokuSenkouB].Get(26 + 50); !v.IsNaN() {
+		t.Fatalf("expected NaN, got %v", v)
+	}
+	if v := ich[IchimokuSenkouB].Get(26 + 51); v.IsNaN() {
+		t.Fatalf("unexpected NaN")
+	}
+}
+
+func TestReadiness(t *testing.T) {
+	rsi := Readiness(RSI(14))
+	for i := 0; i < 30; i++ {
+		if ready := rsi.Ready(); ready != (i > 14) {
+			t.Fatalf("[%d] unexpected ready %v", i, ready)
+		}
+		rsi.Update(testClose.Get(i))
+	}
+	if rsi.Count() != 30 || rsi.Lookback() != 14 {
+		t.Fatalf("unexpected count %d or lookback %d", rsi.Count(), rsi.Lookback())
+	}
+
+	var (
+		t0     = time.Date(2021, 3, 1, 9, 30, 0, 0, time.UTC)
+		pivots = Readiness(Pivots(PivotClassic, nil))
+		mtf    = Readiness(MultiTimeframeAt(func() Study { return SMA(2) }, time.Hour, nil, false))
+	)
+	for i, c := range testCandles()[:60] {
+		ts := t0.Add(time.Duration(i) * 30 * time.Minute)
+		pivots.UpdateCandleAt(ts, c)
+		mtf.UpdateCandleAt(ts, c)
+
+		// the first session closes on the first update of the next day
+		if exp := !ts.Before(t0.Add(14*time.Hour + 30*time.Minute)); pivots.Ready() != exp {
+			t.Fatalf("[%d] pivots: expected %v", i, exp)
+		}
+		// the second hourly bar closes on the first update of the third one
+		if exp := i >= 3; mtf.Ready() != exp {
+			t.Fatalf("[%d] mtf: expected %v", i, exp)
+		}
+	}
+}
@@ -1,5 +1,11 @@
 package ta
 
+import (
+	"math"
+
+	"go.oneofone.dev/ta/decimal"
+)
+
 type MovingAverage interface {
 	Study
 	ma()
@@ -52,7 +58,10 @@ func (l *sma) Update(vs ...Decimal) Decimal {
 	return l.sum / Decimal(l.count)
 }
 
-func (l *sma) Len() int { return l.period }
+func (l *sma) Len() int      { return l.period }
+func (l *sma) Lookback() int { return l.period - 1 }
+func (l *sma) Clone() Study  { return clone(l) }
+func (l *sma) Reset()        { *l = *SMA(l.period).(*sma) }
 
 // EMA - Exponential Moving Average
 // An alias for CustomEMA(period, 2 / (period+1))
@@ -72,6 +81,12 @@ func CustomEMA(period int, k Decimal) MovingAverage {
 	}
 }
 
+// WilderMA - Wilder's Smoothing (also known as SMMA or RMA), used by RSI, ATR and ADX
+// An alias for CustomEMA(period, 1 / period)
+func WilderMA(period int) MovingAverage {
+	return CustomEMA(period, 1/Decimal(period))
+}
+
 var _ Study = (*ema)(nil)
 
 type ema struct {
@@ -106,7 +121,10 @@ func (l *ema) Update(vs ...Decimal) Decimal {
 	return l.prevMA
 }
 
-func (l *ema) Len() int { return l.period }
+func (l *ema) Len() int      { return l.period }
+func (l *ema) Lookback() int { return l.period - 1 }
+func (l *ema) Clone() Study  { return clone(l) }
+func (l *ema) Reset()        { *l = ema{k: l.k, period: l.period} }
 
 func (l *ema) copy() ema {
 	return *l
@@ -183,7 +201,10 @@ func (l *wma) Update(vs ...Decimal) (rv Decimal) {
 	return rv
 }
 
-func (l *wma) Len() int { return l.period }
+func (l *wma) Len() int      { return l.period }
+func (l *wma) Lookback() int { return l.period - 1 }
+func (l *wma) Clone() Study  { return clone(l) }
+func (l *wma) Reset()        { *l = *CustomWMA(l.period, l.weight).(*wma) }
 
 // DEMA - Double Exponential Moving Average
 func DEMA(period int) MovingAverage {
@@ -223,7 +244,16 @@ func (l *dxma) Update(vs ...Decimal) Decimal {
 	return e1*2 - e2
 }
 
-func (l *dxma) Len() int { return l.e1.Len() }
+func (l *dxma) Len() int      { return l.e1.Len() }
+func (l *dxma) Lookback() int { return Lookback(l.e1) + Lookback(l.e2) }
+
+func (l *dxma) Clone() Study { return clone(l) }
+
+func (l *dxma) Reset() {
+	l.e1.Reset()
+	l.e2.Reset()
+	l.idx = 0
+}
 
 // TEMA - Triple Exponential Moving Average
 func TEMA(period int) MovingAverage {
@@ -232,12 +262,19 @@ func TEMA(period int) MovingAverage {
 
 // TripleMA - Triple Moving Average
 func TripleMA(period int, ma MovingAverageFunc) MovingAverage {
+	return newTripleMA(period, ma, false)
+}
+
+// newTripleMA returns a txma, if staged is true each MA only starts once the previous one is past its lookback,
+// the same as TA-Lib's TRIX, use stage instead of Update to get the last MA.
+func newTripleMA(period int, ma MovingAverageFunc, staged bool) *txma {
 	checkPeriod(period, 2)
 	return &txma{
 		e1:     ma(period),
 		e2:     ma(period),
 		e3:     ma(period),
 		period: period,
+		staged: staged,
 	}
 }
 
@@ -250,6 +287,9 @@ type txma struct {
 	idx        int
 	max2       int
 	max3       int
+
+	staged     bool
+	n1, n2, n3 int // the updates of each MA before it's past its lookback
 }
 
 func (l *txma) Update(vs ...Decimal) Decimal {
@@ -270,10 +310,733 @@ func (l *txma) Update(vs ...Decimal) Decimal {
 	return 3*e1 - 3*e2 + e3
 }
 
+// stage feeds v to e1 and each MA the output of the previous one once it's past its lookback,
+// it returns the value of e3 and true once e3 is past its lookback.
+func (l *txma) stage(v Decimal) (Decimal, bool) {
+	if v = l.e1.Update(v); l.n1 < Lookback(l.e1) {
+		l.n1++
+		return 0, false
+	}
+	if v = l.e2.Update(v); l.n2 < Lookback(l.e2) {
+		l.n2++
+		return 0, false
+	}
+	if v = l.e3.Update(v); l.n3 < Lookback(l.e3) {
+		l.n3++
+		return 0, false
+	}
+	return v, true
+}
+
 func (l *txma) Len() int { return l.period }
 
-// TODO:
-// - Trima
-// - KAMA
-// - MAMA/FAMA
-// - T3
+func (l *txma) Lookback() int { return Lookback(l.e1) + Lookback(l.e2) + Lookback(l.e3) }
+
+func (l *txma) Clone() Study { return clone(l) }
+
+func (l *txma) Reset() {
+	l.e1.Reset()
+	l.e2.Reset()
+	l.e3.Reset()
+	l.idx = 0
+	l.n1, l.n2, l.n3 = 0, 0, 0
+}
+
+// TRIMA - Triangular Moving Average
+func TRIMA(period int) MovingAverage {
+	checkPeriod(period, 2)
+	n := period >> 1
+	p1, p2 := n+1, n+1
+	if period%2 == 0 {
+		p1 = n
+	}
+	return &trima{
+		s1:     &sma{data: NewCapped(p1), period: p1},
+		s2:     &sma{data: NewCapped(p2), period: p2},
+		period: period,
+	}
+}
+
+var _ Study = (*trima)(nil)
+
+type trima struct {
+	implMA
+	s1, s2 *sma
+	period int
+}
+
+func (l *trima) Update(vs ...Decimal) (rv Decimal) {
+	for _, v := range vs {
+		rv = l.s2.Update(l.s1.Update(v))
+	}
+	return rv
+}
+
+func (l *trima) Len() int      { return l.period }
+func (l *trima) Lookback() int { return l.s1.Lookback() + l.s2.Lookback() }
+func (l *trima) Clone() Study  { return clone(l) }
+func (l *trima) Reset()        { *l = *TRIMA(l.period).(*trima) }
+
+// KAMA - Kaufman Adaptive Moving Average
+func KAMA(period int) MovingAverage {
+	checkPeriod(period, 2)
+	return &kama{
+		data:   NewCapped(period + 1),
+		period: period,
+	}
+}
+
+var _ Study = (*kama)(nil)
+
+type kama struct {
+	implMA
+	data   *TA
+	sumROC Decimal
+	last   Decimal
+	prev   Decimal
+	period int
+	count  int
+}
+
+const (
+	kamaFast Decimal = 2. / (2 + 1)
+	kamaSlow Decimal = 2. / (30 + 1)
+)
+
+func (l *kama) Update(vs ...Decimal) Decimal {
+	for _, v := range vs {
+		count := l.count
+		if count > 0 {
+			l.sumROC += (v - l.last).Abs()
+		}
+		l.last = v
+
+		if count < l.period {
+			l.data.Set(count, v)
+			l.count++
+			l.prev = v
+			continue
+		}
+
+		var periodROC Decimal
+		if count == l.period {
+			periodROC = v - l.data.Get(0)
+			l.data.Set(count, v)
+			l.prev = l.data.Get(count - 1)
+			l.count++
+		} else {
+			trailing := l.data.Get(1)
+			l.sumROC -= (l.data.Get(0) - trailing).Abs()
+			periodROC = v - trailing
+			l.data.Update(v)
+		}
+
+		er := One
+		if l.sumROC > periodROC && !isZero(l.sumROC) {
+			er = (periodROC / l.sumROC).Abs()
+		}
+		sc := er*(kamaFast-kamaSlow) + kamaSlow
+		sc *= sc
+		l.prev = (v-l.prev)*sc + l.prev
+	}
+	return l.prev
+}
+
+func (l *kama) Len() int      { return l.period }
+func (l *kama) Lookback() int { return l.period }
+func (l *kama) Clone() Study  { return clone(l) }
+func (l *kama) Reset()        { *l = *KAMA(l.period).(*kama) }
+
+// T3 - Triple Exponential Moving Average (T3)
+// An alias for T3Ext(period, 0.7)
+func T3(period int) MovingAverage {
+	return T3Ext(period, 0.7)
+}
+
+// T3Ext - returns an updatable T3 with the given volume factor
+func T3Ext(period int, vFactor Decimal) MovingAverage {
+	checkPeriod(period, 2)
+	l := &t3{period: period}
+	for i := range &l.e {
+		l.e[i] = &ema{k: Decimal(2 / float64(period+1)), period: period}
+	}
+	vf2 := vFactor * vFactor
+	l.c1 = -vf2 * vFactor
+	l.c2 = 3 * (vf2 - l.c1)
+	l.c3 = -6*vf2 - 3*(vFactor-l.c1)
+	l.c4 = 1 + 3*vFactor - l.c1 + 3*vf2
+	return l
+}
+
+var _ Study = (*t3)(nil)
+
+type t3 struct {
+	implMA
+	e              [6]*ema
+	c1, c2, c3, c4 Decimal
+	period         int
+}
+
+func (l *t3) Update(vs ...Decimal) (rv Decimal) {
+	for _, v := range vs {
+		// each ema only starts getting data once the one before it is set
+		rv = v
+		for _, e := range &l.e {
+			ev := e.Update(rv)
+			if !e.set {
+				break
+			}
+			rv = ev
+		}
+	}
+
+	if e := &l.e; e[5].set {
+		rv = l.c1*e[5].prevMA + l.c2*e[4].prevMA + l.c3*e[3].prevMA + l.c4*e[2].prevMA
+	}
+	return rv
+}
+
+func (l *t3) Len() int { return l.period }
+
+func (l *t3) Lookback() (lb int) {
+	for _, e := range &l.e {
+		lb += e.Lookback()
+	}
+	return lb
+}
+
+func (l *t3) Clone() Study { return clone(l) }
+
+func (l *t3) Reset() {
+	for _, e := range &l.e {
+		e.Reset()
+	}
+}
+
+// MAMA - MESA Adaptive Moving Average
+// An alias for MAMAExt(period, 0.5, 0.05), the period is only used for `Len`
+func MAMA(period int) MovingAverage {
+	return newMAMA(period, 0.5, 0.05, false)
+}
+
+// FAMA - Following Adaptive Moving Average
+// same as MAMA, except Update returns FAMA
+func FAMA(period int) MovingAverage {
+	return newMAMA(period, 0.5, 0.05, true)
+}
+
+// MAMAExt - MESA Adaptive Moving Average with the given limits, the period is only used for `Len`
+// Update returns MAMA
+// UpdateAll returns [MAMA, FAMA]
+// ToStudy returns a MovingAverage
+func MAMAExt(period int, fastLimit, slowLimit Decimal) MultiVarStudy {
+	return newMAMA(period, fastLimit, slowLimit, false)
+}
+
+func newMAMA(period int, fastLimit, slowLimit Decimal, isFama bool) *mama {
+	checkPeriod(period, 2)
+	return &mama{
+		fast:   fastLimit,
+		slow:   slowLimit,
+		period: period,
+		isFama: isFama,
+	}
+}
+
+var (
+	_ Study         = (*mama)(nil)
+	_ MultiVarStudy = (*mama)(nil)
+)
+
+type mama struct {
+	implMA
+	price     htPrice
+	ht        htCore
+	prevPhase Decimal
+	mama      Decimal
+	fama      Decimal
+	fast      Decimal
+	slow      Decimal
+	period    int
+	idx       int
+	isFama    bool
+}
+
+func (l *mama) Update(vs ...Decimal) Decimal {
+	out := l.UpdateAll(vs...)
+	if l.isFama {
+		return out[1]
+	}
+	return out[0]
+}
+
+func (l *mama) UpdateAll(vs ...Decimal) []Decimal {
+	for _, v := range vs {
+		l.update(v)
+	}
+	return []Decimal{l.mama, l.fama}
+}
+
+func (l *mama) update(v Decimal) {
+	idx := l.idx
+	l.idx++
+
+	sm, ok := l.price.update(v)
+	if !ok || idx < 12 {
+		return
+	}
+
+	ht := &l.ht
+	ht.step(sm, idx%2 == 0)
+
+	var phase Decimal
+	if ht.inPhase != 0 {
+		phase = (ht.quadrature / ht.inPhase).Atan() * rad2Deg
+	}
+
+	delta := l.prevPhase - phase
+	l.prevPhase = phase
+	if delta < 1 {
+		delta = 1
+	}
+
+	alpha := l.fast
+	if delta > 1 {
+		if alpha = l.fast / delta; alpha < l.slow {
+			alpha = l.slow
+		}
+	}
+
+	l.mama = alpha*v + (1-alpha)*l.mama
+	alpha *= 0.5
+	l.fama = alpha*l.mama + (1-alpha)*l.fama
+
+	ht.updatePeriod()
+}
+
+func (l *mama) Len() int      { return l.period }
+func (l *mama) LenAll() []int { return []int{l.period, l.period} }
+func (l *mama) Lookback() int { return 32 }
+func (l *mama) Clone() Study  { return clone(l) }
+func (l *mama) Reset()        { *l = *newMAMA(l.period, l.fast, l.slow, l.isFama) }
+
+func (l *mama) ToMulti() (MultiVarStudy, bool) { return l, true }
+func (l *mama) ToStudy() (Study, bool)         { return l, true }
+
+// VWMA - Volume Weighted Moving Average, sum(price * volume) / sum(volume) over the period
+// Update expects [volume, price] pairs, same as VWAP, a single value is treated as a price with a volume of 1
+func VWMA(period int) MovingAverage {
+	checkPeriod(period, 2)
+	return &vwma{
+		pv:     NewCapped(period),
+		vol:    NewCapped(period),
+		period: period,
+	}
+}
+
+var _ MovingAverage = (*vwma)(nil)
+
+type vwma struct {
+	implMA
+	pv     *TA
+	vol    *TA
+	sumPV  Decimal
+	sumVol Decimal
+	period int
+}
+
+func (l *vwma) Update(vs ...Decimal) Decimal {
+	switch len(vs) {
+	case 1:
+		l.update(1, vs[0])
+	case 2:
+		l.update(vs[0], vs[1])
+	default:
+		panic("vwma: expected [price] or [volume, price]")
+	}
+	if l.sumVol == 0 {
+		return 0
+	}
+	return l.sumPV / l.sumVol
+}
+
+func (l *vwma) update(vol, price Decimal) {
+	pv := vol * price
+	l.sumPV += pv - l.pv.Update(pv)
+	l.sumVol += vol - l.vol.Update(vol)
+}
+
+func (l *vwma) Len() int      { return l.period }
+func (l *vwma) Lookback() int { return l.period - 1 }
+func (l *vwma) Clone() Study  { return clone(l) }
+func (l *vwma) Reset()        { *l = *VWMA(l.period).(*vwma) }
+
+// HMA - Hull Moving Average, WMA(sqrt(period)) of 2 * WMA(period / 2) - WMA(period)
+func HMA(period int) MovingAverage {
+	checkPeriod(period, 2)
+	return &hma{
+		half:   optionalMA(WMA, period/2),
+		full:   WMA(period),
+		sqrt:   optionalMA(WMA, int(Decimal(period).Sqrt())),
+		period: period,
+	}
+}
+
+var _ MovingAverage = (*hma)(nil)
+
+type hma struct {
+	implMA
+	half, full, sqrt MovingAverage
+	period           int
+}
+
+func (l *hma) Update(vs ...Decimal) (rv Decimal) {
+	for _, v := range vs {
+		half := v
+		if l.half != nil {
+			half = l.half.Update(v)
+		}
+		rv = 2*half - l.full.Update(v)
+		if l.sqrt != nil {
+			rv = l.sqrt.Update(rv)
+		}
+	}
+	return rv
+}
+
+func (l *hma) Len() int      { return l.period }
+func (l *hma) Lookback() int { return Lookback(l.full) + Lookback(l.sqrt) }
+
+func (l *hma) Clone() Study { return clone(l) }
+
+func (l *hma) Reset() {
+	for _, ma := range []MovingAverage{l.half, l.full, l.sqrt} {
+		if ma != nil {
+			ma.Reset()
+		}
+	}
+}
+
+// ZLEMA - Zero Lag Exponential Moving Average, EMA(period) of 2 * price - price (period - 1) / 2 bars ago
+func ZLEMA(period int) MovingAverage {
+	checkPeriod(period, 2)
+	return &zlema{
+		ema:  EMA(period),
+		data: newRing((period-1)/2 + 1),
+	}
+}
+
+var _ MovingAverage = (*zlema)(nil)
+
+type zlema struct {
+	implMA
+	ema  MovingAverage
+	data *TA
+}
+
+func (l *zlema) Update(vs ...Decimal) (rv Decimal) {
+	for _, v := range vs {
+		l.data.Update(v)
+		// the lag is only removed once there's enough data
+		if l.data.Len() == l.data.Cap() {
+			v = 2*v - l.data.Get(0)
+		}
+		rv = l.ema.Update(v)
+	}
+	return rv
+}
+
+func (l *zlema) Len() int      { return l.ema.Len() }
+func (l *zlema) Lookback() int { return l.data.Cap() - 1 + Lookback(l.ema) }
+
+func (l *zlema) Clone() Study { return clone(l) }
+
+func (l *zlema) Reset() {
+	l.ema.Reset()
+	l.data = newRing(l.data.Cap())
+}
+
+// ALMA - Arnaud Legoux Moving Average
+// An alias for ALMAExt(period, 0.85, 6)
+func ALMA(period int) MovingAverage {
+	return ALMAExt(period, 0.85, 6)
+}
+
+// ALMAExt - returns an updatable ALMA with the given offset (0-1) and sigma,
+// the weights are a gaussian curve centered at offset * (period - 1) with a width of period / sigma.
+// before the period is full, the most recent weights are used.
+func ALMAExt(period int, offset, sigma Decimal) MovingAverage {
+	checkPeriod(period, 2)
+	if sigma <= 0 {
+		panic("alma: sigma must be > 0")
+	}
+	var (
+		m = offset * Decimal(period-1)
+		s = Decimal(period) / sigma
+		w = make([]Decimal, period)
+	)
+	for i := range w {
+		d := Decimal(i) - m
+		w[i] = (-(d * d) / (2 * s * s)).Exp()
+	}
+	return &alma{
+		data:    newRing(period),
+		weights: w,
+	}
+}
+
+var _ MovingAverage = (*alma)(nil)
+
+type alma struct {
+	implMA
+	data    *TA
+	weights []Decimal
+}
+
+func (l *alma) Update(vs ...Decimal) Decimal {
+	for _, v := range vs {
+		l.data.Update(v)
+	}
+
+	var (
+		ln        = l.data.Len()
+		w         = l.weights[len(l.weights)-ln:]
+		sum, wsum Decimal
+	)
+	for i := 0; i < ln; i++ {
+		sum += w[i] * l.data.Get(i)
+		wsum += w[i]
+	}
+	return sum / wsum
+}
+
+func (l *alma) Len() int      { return len(l.weights) }
+func (l *alma) Lookback() int { return len(l.weights) - 1 }
+func (l *alma) Clone() Study  { return clone(l) }
+func (l *alma) Reset()        { l.data = newRing(len(l.weights)) }
+
+// McGinley - McGinley Dynamic, md + (price - md) / (period * (price / md)^4), starts at the first price
+func McGinley(period int) MovingAverage {
+	checkPeriod(period, 2)
+	return &mcginley{period: period}
+}
+
+var _ MovingAverage = (*mcginley)(nil)
+
+type mcginley struct {
+	implMA
+	md     Decimal
+	period int
+	set    bool
+}
+
+func (l *mcginley) Update(vs ...Decimal) Decimal {
+	for _, v := range vs {
+		if !l.set {
+			l.md, l.set = v, true
+			continue
+		}
+		// the adjustment is 0 when md is 0 and infinite when v is 0, keep md in both cases
+		if l.md == 0 || v == 0 {
+			continue
+		}
+		l.md += (v - l.md) / (Decimal(l.period) * (v / l.md).Pow(4))
+	}
+	return l.md
+}
+
+func (l *mcginley) Len() int      { return l.period }
+func (l *mcginley) Lookback() int { return l.period - 1 }
+func (l *mcginley) Clone() Study  { return clone(l) }
+func (l *mcginley) Reset()        { l.md, l.set = 0, false }
+
+// VIDYA - Variable Index Dynamic Average
+// An alias for VIDYAExt(period, period)
+func VIDYA(period int) MovingAverage {
+	return VIDYAExt(period, period)
+}
+
+// VIDYAExt - returns an updatable VIDYA, an EMA(period) with k scaled by |CMO(cmoPeriod)| / 100,
+// the CMO is Chande's original (summed, not smoothed) version, it starts at the first price.
+func VIDYAExt(period, cmoPeriod int) MovingAverage {
+	checkPeriod(period, 2)
+	checkPeriod(cmoPeriod, 1)
+	return &vidya{
+		up:     NewCapped(cmoPeriod),
+		down:   NewCapped(cmoPeriod),
+		k:      2 / Decimal(period+1),
+		period: period,
+	}
+}
+
+var _ MovingAverage = (*vidya)(nil)
+
+type vidya struct {
+	implMA
+	up, down     *TA
+	sumUp, sumDn Decimal
+	k            Decimal
+	last         Decimal
+	vidya        Decimal
+	period       int
+	set          bool
+}
+
+func (l *vidya) Update(vs ...Decimal) Decimal {
+	for _, v := range vs {
+		if !l.set {
+			l.last, l.vidya, l.set = v, v, true
+			continue
+		}
+
+		var up, down Decimal
+		if d := v - l.last; d > 0 {
+			up = d
+		} else {
+			down = -d
+		}
+		l.last = v
+		l.sumUp += up - l.up.Update(up)
+		l.sumDn += down - l.down.Update(down)
+
+		var cmo Decimal
+		if sum := l.sumUp + l.sumDn; !isZero(sum) {
+			cmo = ((l.sumUp - l.sumDn) / sum).Abs()
+		}
+		k := l.k * cmo
+		l.vidya = k*v + (1-k)*l.vidya
+	}
+	return l.vidya
+}
+
+func (l *vidya) Len() int      { return l.period }
+func (l *vidya) Lookback() int { return l.up.Cap() }
+func (l *vidya) Clone() Study  { return clone(l) }
+func (l *vidya) Reset()        { *l = *VIDYAExt(l.period, l.up.Cap()).(*vidya) }
+
+// FRAMA - Ehlers' Fractal Adaptive Moving Average, odd periods are rounded up,
+// it returns the price until there's a full period.
+func FRAMA(period int) MovingAverage {
+	checkPeriod(period, 2)
+	if period%2 == 1 {
+		period++
+	}
+	return &frama{
+		data:   newRing(period),
+		period: period,
+	}
+}
+
+var _ MovingAverage = (*frama)(nil)
+
+const ln2 Decimal = math.Ln2
+
+type frama struct {
+	implMA
+	data   *TA
+	dimen  Decimal
+	filt   Decimal
+	period int
+}
+
+func (l *frama) Update(vs ...Decimal) Decimal {
+	for _, v := range vs {
+		l.update(v)
+	}
+	return l.filt
+}
+
+func (l *frama) update(v Decimal) {
+	l.data.Update(v)
+	if l.data.Len() < l.period {
+		l.filt = v
+		return
+	}
+
+	var (
+		half       = l.period / 2
+		hi1, lo1   = l.data.Get(half), l.data.Get(half)
+		hi2, lo2   = l.data.Get(0), l.data.Get(0)
+		n1, n2, n3 Decimal
+	)
+	for i := 0; i < half; i++ {
+		newer, older := l.data.Get(half+i), l.data.Get(i)
+		hi1, lo1 = decimal.Max(hi1, newer), decimal.Min(lo1, newer)
+		hi2, lo2 = decimal.Max(hi2, older), decimal.Min(lo2, older)
+	}
+	n1 = (hi1 - lo1) / Decimal(half)
+	n2 = (hi2 - lo2) / Decimal(half)
+	n3 = (decimal.Max(hi1, hi2) - decimal.Min(lo1, lo2)) / Decimal(l.period)
+
+	// the dimension is kept on flat windows, same as Ehlers' code
+	if n1 > 0 && n2 > 0 && n3 > 0 {
+		l.dimen = ((n1 + n2).Log() - n3.Log()) / ln2
+	}
+
+	alpha := (-4.6 * (l.dimen - 1)).Exp()
+	alpha = decimal.Min(decimal.Max(alpha, 0.01), 1)
+	l.filt = alpha*v + (1-alpha)*l.filt
+}
+
+func (l *frama) Len() int      { return l.period }
+func (l *frama) Lookback() int { return l.period - 1 }
+func (l *frama) Clone() Study  { return clone(l) }
+func (l *frama) Reset()        { *l = *FRAMA(l.period).(*frama) }
+
+// JMA - Jurik style Moving Average, a close approximation of Mark Jurik's JMA
+// An alias for JMAExt(period, 0, 2)
+func JMA(period int) MovingAverage {
+	return JMAExt(period, 0, 2)
+}
+
+// JMAExt - returns an updatable JMA with the given phase (-100 to 100) and power,
+// it starts at the first price.
+func JMAExt(period int, phase, power Decimal) MovingAverage {
+	checkPeriod(period, 2)
+	var (
+		beta  = 0.45 * Decimal(period-1) / (0.45*Decimal(period-1) + 2)
+		ratio = phase/100 + 1.5
+	)
+	switch {
+	case phase < -100:
+		ratio = 0.5
+	case phase > 100:
+		ratio = 2.5
+	}
+	return &jma{
+		beta:   beta,
+		alpha:  beta.Pow(power),
+		ratio:  ratio,
+		period: period,
+	}
+}
+
+var _ MovingAverage = (*jma)(nil)
+
+type jma struct {
+	implMA
+	beta, alpha, ratio Decimal
+	e0, e1, e2, jma    Decimal
+	period             int
+	set                bool
+}
+
+func (l *jma) Update(vs ...Decimal) Decimal {
+	for _, v := range vs {
+		if !l.set {
+			l.e0, l.jma, l.set = v, v, true
+			continue
+		}
+		a := l.alpha
+		l.e0 = (1-a)*v + a*l.e0
+		l.e1 = (v-l.e0)*(1-l.beta) + l.beta*l.e1
+		l.e2 = (l.e0+l.ratio*l.e1-l.jma)*(1-a)*(1-a) + a*a*l.e2
+		l.jma += l.e2
+	}
+	return l.jma
+}
+
+func (l *jma) Len() int      { return l.period }
+func (l *jma) Lookback() int { return l.period - 1 }
+func (l *jma) Clone() Study  { return clone(l) }
+func (l *jma) Reset()        { *l = jma{beta: l.beta, alpha: l.alpha, ratio: l.ratio, period: l.period} }
@@ -0,0 +1,207 @@
+package ta
+
+import "go.oneofone.dev/ta/decimal"
+
+const (
+	rocMom uint8 = iota
+	rocROC
+	rocROCP
+	rocROCR
+	rocROCR100
+)
+
+// MOM - Momentum, price - prevPrice
+func MOM(period int) Study {
+	return newROC(period, rocMom)
+}
+
+// ROC - Rate of change, ((price / prevPrice) - 1) * 100
+func ROC(period int) Study {
+	return newROC(period, rocROC)
+}
+
+// ROCP - Rate of change Percentage, (price - prevPrice) / prevPrice
+func ROCP(period int) Study {
+	return newROC(period, rocROCP)
+}
+
+// ROCR - Rate of change ratio, price / prevPrice
+func ROCR(period int) Study {
+	return newROC(period, rocROCR)
+}
+
+// ROCR100 - Rate of change ratio 100 scale, (price / prevPrice) * 100
+func ROCR100(period int) Study {
+	return newROC(period, rocROCR100)
+}
+
+func newROC(period int, mode uint8) *roc {
+	checkPeriod(period, 1)
+	return &roc{
+		data:   NewCapped(period),
+		period: period,
+		mode:   mode,
+	}
+}
+
+var _ Study = (*roc)(nil)
+
+type roc struct {
+	noMulti
+	data   *TA
+	period int
+	mode   uint8
+}
+
+func (s *roc) Update(vs ...Decimal) (out Decimal) {
+	for _, v := range vs {
+		prev := s.data.Update(v)
+		if s.mode == rocMom {
+			out = v - prev
+			continue
+		}
+
+		// same as TA-Lib, a zero previous price returns 0
+		if out = 0; prev == 0 {
+			continue
+		}
+
+		switch s.mode {
+		case rocROC:
+			out = (v/prev - 1) * 100
+		case rocROCP:
+			out = (v - prev) / prev
+		case rocROCR:
+			out = v / prev
+		case rocROCR100:
+			out = v / prev * 100
+		}
+	}
+	return
+}
+
+func (s *roc) Len() int      { return s.period }
+func (s *roc) Lookback() int { return s.period }
+func (s *roc) Clone() Study  { return clone(s) }
+func (s *roc) Reset()        { *s = *newROC(s.period, s.mode) }
+
+// TRIX - 1-day Rate-Of-Change (ROC) of a Triple Smooth EMA
+func TRIX(period int) Study {
+	return &trix{
+		ma:     newTripleMA(period, EMA, true),
+		roc:    newROC(1, rocROC),
+		period: period,
+	}
+}
+
+var _ Study = (*trix)(nil)
+
+type trix struct {
+	noMulti
+	ma     *txma
+	roc    *roc
+	period int
+}
+
+func (s *trix) Update(vs ...Decimal) (out Decimal) {
+	for _, v := range vs {
+		out = 0
+		if v, ok := s.ma.stage(v); ok {
+			out = s.roc.Update(v)
+		}
+	}
+	return
+}
+
+func (s *trix) Len() int { return s.period }
+
+func (s *trix) Lookback() int { return s.ma.Lookback() + s.roc.Lookback() }
+
+func (s *trix) Clone() Study { return clone(s) }
+func (s *trix) Reset()       { *s = *TRIX(s.period).(*trix) }
+
+// APO - Absolute Price Oscillator, ma(fastPeriod) - ma(slowPeriod), if ma is nil, SMA is used.
+func APO(fastPeriod, slowPeriod int, ma MovingAverageFunc) Study {
+	return newPO(fastPeriod, slowPeriod, ma, false)
+}
+
+// PPO - Percentage Price Oscillator, (ma(fastPeriod) - ma(slowPeriod)) / ma(slowPeriod) * 100, if ma is nil, SMA is used.
+func PPO(fastPeriod, slowPeriod int, ma MovingAverageFunc) Study {
+	return newPO(fastPeriod, slowPeriod, ma, true)
+}
+
+func newPO(fastPeriod, slowPeriod int, ma MovingAverageFunc, pct bool) *po {
+	checkPeriod(fastPeriod, 2)
+	checkPeriod(slowPeriod, 2)
+	if ma == nil {
+		ma = SMA
+	}
+	// same as TA-Lib, the periods are swapped if needed
+	if slowPeriod < fastPeriod {
+		fastPeriod, slowPeriod = slowPeriod, fastPeriod
+	}
+	return &po{
+		fast: ma(fastPeriod),
+		slow: ma(slowPeriod),
+		pct:  pct,
+	}
+}
+
+var _ Study = (*po)(nil)
+
+type po struct {
+	noMulti
+	fast MovingAverage
+	slow MovingAverage
+	pct  bool
+}
+
+func (s *po) Update(vs ...Decimal) Decimal {
+	fast, slow := s.fast.Update(vs...), s.slow.Update(vs...)
+	if !s.pct {
+		return fast - slow
+	}
+	if isZero(slow) {
+		return 0
+	}
+	return (fast - slow) / slow * 100
+}
+
+func (s *po) Len() int      { return s.slow.Len() }
+func (s *po) Lookback() int { return decimal.Max(Lookback(s.fast), Lookback(s.slow)) }
+
+func (s *po) Clone() Study { return clone(s) }
+
+func (s *po) Reset() {
+	s.fast.Reset()
+	s.slow.Reset()
+}
+
+// BOP - Balance Of Power, (close - open) / (high - low)
+func BOP() CandleStudy {
+	return &bop{}
+}
+
+var _ CandleStudy = (*bop)(nil)
+
+type bop struct{}
+
+func (s *bop) Update(vs ...Decimal) Decimal      { return s.update(barFromValues(vs)) }
+func (s *bop) UpdateAll(vs ...Decimal) []Decimal { return []Decimal{s.Update(vs...)} }
+func (s *bop) UpdateCandle(c *Candle) []Decimal  { return []Decimal{s.update(barFromCandle(c))} }
+
+func (s *bop) update(b bar) Decimal {
+	if hl := b.high - b.low; hl >= 1e-14 {
+		return (b.close - b.open) / hl
+	}
+	return 0
+}
+
+func (s *bop) Len() int      { return 0 }
+func (s *bop) LenAll() []int { return []int{0} }
+func (s *bop) Lookback() int { return 0 }
+func (s *bop) Clone() Study  { return clone(s) }
+func (s *bop) Reset()        {}
+
+func (s *bop) ToStudy() (Study, bool)         { return s, true }
+func (s *bop) ToMulti() (MultiVarStudy, bool) { return s, true }
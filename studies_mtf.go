@@ -0,0 +1,173 @@
+package ta
+
+import "time"
+
+// TimeframeStudy is returned by MultiTimeframe and MultiTimeframeAt
+type TimeframeStudy interface {
+	TimedStudy
+
+	// Closed returns the values of the wrapped study on the last closed higher timeframe bar,
+	// they are all 0 until the first bar closes.
+	Closed() []Decimal
+
+	// Forming returns a copy of the forming higher timeframe bar, nil if there isn't one
+	Forming() *Candle
+}
+
+// MultiTimeframe returns a study that aggregates every n updates into a higher timeframe bar and only updates
+// the study returned by newStudy when a bar closes, the values of the bar follow the same rules as CandleStudy.
+// If the wrapped study is a CandleStudy, it gets the full bar, otherwise it gets the close.
+// If provisional is false, every update returns the values of the last closed bar,
+// otherwise it returns the values of a clone of the study updated with the forming bar,
+// the same values the study would return if the bar closed on that update.
+// UpdateCandleAt ignores the timestamp.
+// Update returns the first value of UpdateAll
+func MultiTimeframe(newStudy func() Study, n int, provisional bool) TimeframeStudy {
+	checkPeriod(n, 1)
+	return newMTF(newStudy, nil, n, provisional)
+}
+
+// MultiTimeframeAt same as MultiTimeframe, except the higher timeframe bars are built by a BarBuilder
+// for the given interval and session, a bar closes on the first update after it ends.
+// It can only be updated with UpdateCandleAt, the other Update funcs panic.
+func MultiTimeframeAt(newStudy func() Study, interval time.Duration, session SessionFunc, provisional bool) TimeframeStudy {
+	return newMTF(newStudy, NewBarBuilder(interval, session, false), 0, provisional)
+}
+
+func newMTF(newStudy func() Study, agg *BarBuilder, n int, provisional bool) *mtf {
+	s := &mtf{
+		newStudy: newStudy,
+		s:        newStudy(),
+		agg:      agg,
+		n:        n,
+		prov:     provisional,
+	}
+	s.last = make([]Decimal, len(s.LenAll()))
+	return s
+}
+
+var _ TimeframeStudy = (*mtf)(nil)
+
+type mtf struct {
+	newStudy func() Study
+	s        Study
+	agg      *BarBuilder // nil if the bars are count based
+
+	last []Decimal
+
+	cur      bar // the forming bar of a count based study
+	count, n int
+	bars     int // the number of closed bars
+
+	prov bool
+}
+
+func (s *mtf) Update(vs ...Decimal) Decimal      { return s.UpdateAll(vs...)[0] }
+func (s *mtf) UpdateAll(vs ...Decimal) []Decimal { return s.update(barFromValues(vs)) }
+func (s *mtf) UpdateCandle(c *Candle) []Decimal  { return s.update(barFromCandle(c)) }
+
+func (s *mtf) UpdateCandleAt(ts time.Time, c *Candle) []Decimal {
+	if s.agg == nil {
+		return s.UpdateCandle(c)
+	}
+
+	for _, b := range s.agg.UpdateCandle(ts, c) {
+		s.close(barFromCandle(&b.Candle))
+	}
+	if cur := s.agg.Current(); cur != nil {
+		return s.provisional(barFromCandle(&cur.Candle))
+	}
+	return s.Closed()
+}
+
+func (s *mtf) update(b bar) []Decimal {
+	if s.agg != nil {
+		panic("mtf: time based studies can only be updated with UpdateCandleAt")
+	}
+
+	if s.count == 0 {
+		s.cur = b
+	} else {
+		s.cur.merge(b)
+	}
+
+	if s.count++; s.count == s.n {
+		s.count = 0
+		s.close(s.cur)
+		return s.Closed()
+	}
+	return s.provisional(s.cur)
+}
+
+func (s *mtf) close(b bar) {
+	s.last = updateWithBar(s.s, b)
+	s.bars++
+}
+
+// provisional returns the values of the study if b closed now, without changing it
+func (s *mtf) provisional(b bar) []Decimal {
+	if !s.prov {
+		return s.Closed()
+	}
+	return updateWithBar(s.s.Clone(), b)
+}
+
+func (s *mtf) Closed() []Decimal {
+	return append([]Decimal(nil), s.last...)
+}
+
+func (s *mtf) Forming() *Candle {
+	if s.agg != nil {
+		if cur := s.agg.Current(); cur != nil {
+			return &cur.Candle
+		}
+		return nil
+	}
+	if s.count == 0 {
+		return nil
+	}
+	return s.cur.candle()
+}
+
+func (s *mtf) Len() int { return 0 }
+
+func (s *mtf) LenAll() []int {
+	if ms, ok := s.s.ToMulti(); ok {
+		return make([]int, len(ms.LenAll()))
+	}
+	return []int{0}
+}
+
+// Lookback returns the lookback of the wrapped study in updates, for time based studies it's in higher timeframe bars
+func (s *mtf) Lookback() int {
+	if s.agg != nil {
+		return Lookback(s.s)
+	}
+	return (Lookback(s.s)+1)*s.n - 1
+}
+
+func (s *mtf) Clone() Study { return clone(s) }
+
+func (s *mtf) Reset() {
+	agg := s.agg
+	if agg != nil {
+		agg = NewBarBuilder(agg.interval, agg.session, agg.fill)
+	}
+	*s = *newMTF(s.newStudy, agg, s.n, s.prov)
+}
+
+func (s *mtf) ready() bool { return s.bars > Lookback(s.s) }
+
+func (s *mtf) ToStudy() (Study, bool)         { return s, true }
+func (s *mtf) ToMulti() (MultiVarStudy, bool) { return s, true }
+
+// updateWithBar updates s with the full bar if it's a CandleStudy, otherwise with the close
+func updateWithBar(s Study, b bar) []Decimal {
+	if cs, ok := s.(CandleStudy); ok {
+		return cs.UpdateCandle(b.candle())
+	}
+	if ms, ok := s.ToMulti(); ok {
+		return ms.UpdateAll(b.close)
+	}
+	return []Decimal{s.Update(b.close)}
+}
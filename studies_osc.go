@@ -0,0 +1,329 @@
+package ta
+
+import "sort"
+
+// StochFast - Fast Stochastic Oscillator, %D is ma(dPeriod) of the raw %K, if ma is nil, SMA is used.
+// Update returns %K
+// UpdateAll returns [%K, %D]
+func StochFast(kPeriod, dPeriod int, ma MovingAverageFunc) CandleStudy {
+	return StochFull(kPeriod, 1, dPeriod, ma, ma)
+}
+
+// StochSlow - Slow Stochastic Oscillator, %K is ma(slowing) of the raw %K and %D is ma(dPeriod) of the slow %K,
+// if ma is nil, SMA is used.
+// Update returns %K
+// UpdateAll returns [%K, %D]
+func StochSlow(kPeriod, slowing, dPeriod int, ma MovingAverageFunc) CandleStudy {
+	return StochFull(kPeriod, slowing, dPeriod, ma, ma)
+}
+
+// StochFull - Full Stochastic Oscillator, same as TA-Lib's STOCH
+// the raw %K is smoothed with kMA(slowing) and %D is dMA(dPeriod) of the smoothed %K,
+// a nil ma defaults to SMA and a period < 2 disables the smoothing.
+// Update returns %K
+// UpdateAll returns [%K, %D]
+func StochFull(kPeriod, slowing, dPeriod int, kMA, dMA MovingAverageFunc) CandleStudy {
+	return newStoch(kPeriod, slowing, dPeriod, kMA, dMA)
+}
+
+func newStoch(kPeriod, slowing, dPeriod int, kMA, dMA MovingAverageFunc) *stoch {
+	checkPeriod(kPeriod, 1)
+	return &stoch{
+		high:    newRing(kPeriod),
+		low:     newRing(kPeriod),
+		kMA:     optionalMA(kMA, slowing),
+		dMA:     optionalMA(dMA, dPeriod),
+		kPeriod: kPeriod,
+	}
+}
+
+// optionalMA returns fn(period), nil if period < 2
+func optionalMA(fn MovingAverageFunc, period int) MovingAverage {
+	if period < 2 {
+		return nil
+	}
+	if fn == nil {
+		fn = SMA
+	}
+	return fn(period)
+}
+
+var _ CandleStudy = (*stoch)(nil)
+
+type stoch struct {
+	high *TA
+	low  *TA
+	kMA  MovingAverage
+	dMA  MovingAverage
+
+	k Decimal
+	d Decimal
+
+	kPeriod int
+	kCount  int
+}
+
+func (s *stoch) Update(vs ...Decimal) Decimal      { return s.UpdateAll(vs...)[0] }
+func (s *stoch) UpdateAll(vs ...Decimal) []Decimal { return s.update(barFromValues(vs)) }
+func (s *stoch) UpdateCandle(c *Candle) []Decimal  { return s.update(barFromCandle(c)) }
+
+func (s *stoch) update(b bar) []Decimal {
+	return s.updateValues(b.high, b.low, b.close)
+}
+
+func (s *stoch) updateValues(high, low, close Decimal) []Decimal {
+	s.high.Update(high)
+	s.low.Update(low)
+
+	hi, lo := s.high.Max(), s.low.Min()
+	var k Decimal
+	if diff := (hi - lo) / 100; diff != 0 {
+		k = (close - lo) / diff
+	}
+
+	// the smoothing only starts once the window is full, same as TA-Lib
+	if s.high.Len() < s.kPeriod {
+		s.k, s.d = k, k
+		return []Decimal{k, k}
+	}
+
+	if s.kMA != nil {
+		k = s.kMA.Update(k)
+		if s.kCount < s.kMA.Len() {
+			s.kCount++
+		}
+		if s.kCount < s.kMA.Len() {
+			s.k, s.d = k, k
+			return []Decimal{k, k}
+		}
+	}
+
+	s.k, s.d = k, k
+	if s.dMA != nil {
+		s.d = s.dMA.Update(k)
+	}
+	return []Decimal{s.k, s.d}
+}
+
+func (s *stoch) Len() int      { return s.kPeriod }
+func (s *stoch) Lookback() int { return s.kPeriod - 1 + Lookback(s.kMA) + Lookback(s.dMA) }
+
+func (s *stoch) Clone() Study { return clone(s) }
+
+func (s *stoch) Reset() {
+	for _, ma := range []MovingAverage{s.kMA, s.dMA} {
+		if ma != nil {
+			ma.Reset()
+		}
+	}
+	*s = stoch{
+		high:    newRing(s.kPeriod),
+		low:     newRing(s.kPeriod),
+		kMA:     s.kMA,
+		dMA:     s.dMA,
+		kPeriod: s.kPeriod,
+	}
+}
+func (s *stoch) LenAll() []int {
+	ln := s.Len()
+	return []int{ln, ln}
+}
+
+func (s *stoch) ToStudy() (Study, bool)         { return s, true }
+func (s *stoch) ToMulti() (MultiVarStudy, bool) { return s, true }
+
+// StochRSI - Stochastic RSI, the fast stochastic of RSI(period), same as TA-Lib's STOCHRSI
+// if ma is nil, SMA is used for %D.
+// Update returns %K
+// UpdateAll returns [%K, %D]
+func StochRSI(period, kPeriod, dPeriod int, ma MovingAverageFunc) MultiVarStudy {
+	return &stochRSI{
+		rsi: RSI(period).(*rsi),
+		st:  newStoch(kPeriod, 1, dPeriod, nil, ma),
+	}
+}
+
+var _ MultiVarStudy = (*stochRSI)(nil)
+
+type stochRSI struct {
+	rsi *rsi
+	st  *stoch
+}
+
+func (s *stochRSI) Update(vs ...Decimal) Decimal {
+	return s.UpdateAll(vs...)[0]
+}
+
+func (s *stochRSI) UpdateAll(vs ...Decimal) []Decimal {
+	v := s.rsi.Update(vs...)
+	// rsi's first period values are garbage
+	if s.rsi.idx <= s.rsi.period {
+		return []Decimal{0, 0}
+	}
+	return s.st.updateValues(v, v, v)
+}
+
+func (s *stochRSI) Len() int      { return s.st.Len() }
+func (s *stochRSI) LenAll() []int { return s.st.LenAll() }
+func (s *stochRSI) Lookback() int { return s.rsi.Lookback() + s.st.Lookback() }
+
+func (s *stochRSI) Clone() Study { return clone(s) }
+
+func (s *stochRSI) Reset() {
+	s.rsi.Reset()
+	s.st.Reset()
+}
+
+func (s *stochRSI) ToStudy() (Study, bool)         { return s, true }
+func (s *stochRSI) ToMulti() (MultiVarStudy, bool) { return s, true }
+
+// WilliamsR - Williams' %R
+func WilliamsR(period int) CandleStudy {
+	checkPeriod(period, 2)
+	return &willr{
+		high: newRing(period),
+		low:  newRing(period),
+	}
+}
+
+var _ CandleStudy = (*willr)(nil)
+
+type willr struct {
+	high *TA
+	low  *TA
+}
+
+func (s *willr) Update(vs ...Decimal) Decimal      { return s.update(barFromValues(vs)) }
+func (s *willr) UpdateAll(vs ...Decimal) []Decimal { return []Decimal{s.Update(vs...)} }
+func (s *willr) UpdateCandle(c *Candle) []Decimal  { return []Decimal{s.update(barFromCandle(c))} }
+
+func (s *willr) update(b bar) Decimal {
+	s.high.Update(b.high)
+	s.low.Update(b.low)
+
+	hi, lo := s.high.Max(), s.low.Min()
+	if diff := (hi - lo) / -100; diff != 0 {
+		return (hi - b.close) / diff
+	}
+	return 0
+}
+
+func (s *willr) Len() int      { return s.high.Cap() }
+func (s *willr) LenAll() []int { return []int{s.Len()} }
+func (s *willr) Lookback() int { return s.high.Cap() - 1 }
+func (s *willr) Clone() Study  { return clone(s) }
+func (s *willr) Reset()        { *s = *WilliamsR(s.high.Cap()).(*willr) }
+
+func (s *willr) ToStudy() (Study, bool)         { return s, true }
+func (s *willr) ToMulti() (MultiVarStudy, bool) { return s, true }
+
+// CCI - Commodity Channel Index, uses the typical price (hlc3)
+func CCI(period int) CandleStudy {
+	checkPeriod(period, 2)
+	return &cci{
+		data:   newRing(period),
+		period: period,
+	}
+}
+
+var _ CandleStudy = (*cci)(nil)
+
+type cci struct {
+	data   *TA
+	period int
+}
+
+func (s *cci) Update(vs ...Decimal) Decimal      { return s.update(barFromValues(vs)) }
+func (s *cci) UpdateAll(vs ...Decimal) []Decimal { return []Decimal{s.Update(vs...)} }
+func (s *cci) UpdateCandle(c *Candle) []Decimal  { return []Decimal{s.update(barFromCandle(c))} }
+
+func (s *cci) update(b bar) Decimal {
+	tp := (b.high + b.low + b.close) / 3
+	s.data.Update(tp)
+
+	avg := s.data.Avg()
+	var dev Decimal
+	for _, v := range s.data.v {
+		dev += (v - avg).Abs()
+	}
+	dev /= Decimal(s.data.Len())
+
+	if d := tp - avg; d != 0 && dev != 0 {
+		return d / (0.015 * dev)
+	}
+	return 0
+}
+
+func (s *cci) Len() int      { return s.period }
+func (s *cci) LenAll() []int { return []int{s.period} }
+func (s *cci) Lookback() int { return s.period - 1 }
+func (s *cci) Clone() Study  { return clone(s) }
+func (s *cci) Reset()        { *s = *CCI(s.period).(*cci) }
+
+func (s *cci) ToStudy() (Study, bool)         { return s, true }
+func (s *cci) ToMulti() (MultiVarStudy, bool) { return s, true }
+
+// UltimateOscillator - Ultimate Oscillator, the periods are sorted,
+// the shortest one has a weight of 4, the middle 2 and the longest 1.
+func UltimateOscillator(period1, period2, period3 int) CandleStudy {
+	ps := []int{period1, period2, period3}
+	sort.Ints(ps)
+	checkPeriod(ps[0], 1)
+
+	s := &ultosc{period: ps[2]}
+	for i, p := range ps {
+		s.bp[i], s.tr[i] = NewCapped(p), NewCapped(p)
+	}
+	return s
+}
+
+var _ CandleStudy = (*ultosc)(nil)
+
+type ultosc struct {
+	bp, tr       [3]*TA
+	bpSum, trSum [3]Decimal
+	prevClose    Decimal
+	period       int
+	set          bool
+}
+
+func (s *ultosc) Update(vs ...Decimal) Decimal      { return s.update(barFromValues(vs)) }
+func (s *ultosc) UpdateAll(vs ...Decimal) []Decimal { return []Decimal{s.Update(vs...)} }
+func (s *ultosc) UpdateCandle(c *Candle) []Decimal  { return []Decimal{s.update(barFromCandle(c))} }
+
+func (s *ultosc) update(b bar) (out Decimal) {
+	// the first bar doesn't have a previous close, so it doesn't count
+	if !s.set {
+		s.prevClose, s.set = b.close, true
+		return 0
+	}
+
+	lo := b.low
+	if s.prevClose < lo {
+		lo = s.prevClose
+	}
+	bp, tr := b.close-lo, b.trueRange(s.prevClose)
+	s.prevClose = b.close
+
+	for i, w := range &[...]Decimal{4, 2, 1} {
+		s.bpSum[i] += bp - s.bp[i].Update(bp)
+		s.trSum[i] += tr - s.tr[i].Update(tr)
+		if !isZero(s.trSum[i]) {
+			out += w * (s.bpSum[i] / s.trSum[i])
+		}
+	}
+	return 100 * (out / 7)
+}
+
+func (s *ultosc) Len() int      { return s.period }
+func (s *ultosc) LenAll() []int { return []int{s.period} }
+func (s *ultosc) Lookback() int { return s.period }
+
+func (s *ultosc) Clone() Study { return clone(s) }
+
+func (s *ultosc) Reset() {
+	*s = *UltimateOscillator(s.bp[0].Cap(), s.bp[1].Cap(), s.bp[2].Cap()).(*ultosc)
+}
+
+func (s *ultosc) ToStudy() (Study, bool)         { return s, true }
+func (s *ultosc) ToMulti() (MultiVarStudy, bool) { return s, true }
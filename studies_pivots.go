@@ -0,0 +1,164 @@
+package ta
+
+import (
+	"math"
+	"time"
+)
+
+// PivotMethod is the formula used to calculate the pivot levels
+type PivotMethod uint8
+
+const (
+	// PivotClassic is the floor trader pivots, P = (H + L + C) / 3
+	PivotClassic PivotMethod = iota
+	// PivotFibonacci uses the classic P and the 0.382, 0.618 and 1 ratios of the range
+	PivotFibonacci
+	// PivotCamarilla uses the close and 1.1/12, 1.1/6 and 1.1/4 of the range
+	PivotCamarilla
+	// PivotWoodie weights the close, P = (H + L + 2C) / 4
+	PivotWoodie
+	// PivotDeMark depends on the open vs close, it only defines R1 and S1, the other levels are NaN
+	PivotDeMark
+)
+
+// Pivot output indices
+const (
+	PivotP = iota
+	PivotR1
+	PivotR2
+	PivotR3
+	PivotS1
+	PivotS2
+	PivotS3
+)
+
+// PivotLevels returns [P, R1, R2, R3, S1, S2, S3] for the given session candle
+func PivotLevels(m PivotMethod, c *Candle) []Decimal {
+	return pivotLevels(m, barFromCandle(c))
+}
+
+func pivotLevels(m PivotMethod, b bar) []Decimal {
+	var (
+		h, l, c = b.high, b.low, b.close
+		rng     = h - l
+		p       = (h + l + c) / 3
+	)
+
+	switch m {
+	case PivotFibonacci:
+		return []Decimal{p, p + 0.382*rng, p + 0.618*rng, p + rng, p - 0.382*rng, p - 0.618*rng, p - rng}
+	case PivotCamarilla:
+		rng *= 1.1
+		return []Decimal{p, c + rng/12, c + rng/6, c + rng/4, c - rng/12, c - rng/6, c - rng/4}
+	case PivotWoodie:
+		p = (h + l + 2*c) / 4
+	case PivotDeMark:
+		var x Decimal
+		switch {
+		case c < b.open:
+			x = h + 2*l + c
+		case c > b.open:
+			x = 2*h + l + c
+		default:
+			x = h + l + 2*c
+		}
+		nan := Decimal(math.NaN())
+		return []Decimal{x / 4, x/2 - l, nan, nan, x/2 - h, nan, nan}
+	}
+
+	return []Decimal{p, 2*p - l, p + rng, h + 2*(p-l), 2*p - h, p - rng, l - 2*(h-p)}
+}
+
+// SessionFunc returns the start of the session t belongs to
+type SessionFunc func(t time.Time) time.Time
+
+// DailySessions returns a SessionFunc for sessions that start every day at the given offset from midnight in loc,
+// for example DailySessions(nyc, 18*time.Hour) for futures, if loc is nil, the location of each timestamp is used.
+func DailySessions(loc *time.Location, start time.Duration) SessionFunc {
+	return func(t time.Time) time.Time {
+		if loc != nil {
+			t = t.In(loc)
+		}
+		t = t.Add(-start)
+		y, m, d := t.Date()
+		return time.Date(y, m, d, 0, 0, 0, 0, t.Location()).Add(start)
+	}
+}
+
+// TimedStudy is a study that needs the time of each candle, for example session based studies
+type TimedStudy interface {
+	CandleStudy
+
+	// UpdateCandleAt same as UpdateCandle, ts is the time of the candle
+	UpdateCandleAt(ts time.Time, c *Candle) []Decimal
+}
+
+// Pivots returns a pivot points study, the levels are calculated from the OHLC of the previous session,
+// if session is nil, DailySessions(nil, 0) is used.
+// UpdateCandleAt takes intraday candles and starts a new session when session(ts) changes,
+// the other Update funcs treat each call as a full session.
+// Update returns P
+// UpdateAll returns [P, R1, R2, R3, S1, S2, S3], all zeros until the first session is done
+func Pivots(m PivotMethod, session SessionFunc) TimedStudy {
+	if m > PivotDeMark {
+		panic("pivots: invalid method")
+	}
+	if session == nil {
+		session = DailySessions(nil, 0)
+	}
+	return &pivots{
+		levels:  make([]Decimal, 7),
+		session: session,
+		method:  m,
+	}
+}
+
+var _ TimedStudy = (*pivots)(nil)
+
+type pivots struct {
+	session SessionFunc
+	start   time.Time
+	cur     bar
+	levels  []Decimal
+	method  PivotMethod
+	set     bool
+	closed  bool // at least one session was closed
+}
+
+func (s *pivots) Update(vs ...Decimal) Decimal      { return s.UpdateAll(vs...)[PivotP] }
+func (s *pivots) UpdateAll(vs ...Decimal) []Decimal { return s.update(barFromValues(vs)) }
+func (s *pivots) UpdateCandle(c *Candle) []Decimal  { return s.update(barFromCandle(c)) }
+
+func (s *pivots) UpdateCandleAt(ts time.Time, c *Candle) []Decimal {
+	b := barFromCandle(c)
+	if start := s.session(ts); !s.set || !start.Equal(s.start) {
+		s.start = start
+		return s.update(b)
+	}
+
+	s.cur.merge(b)
+	return s.values()
+}
+
+// update starts a new session with b
+func (s *pivots) update(b bar) []Decimal {
+	if s.set {
+		s.levels, s.closed = pivotLevels(s.method, s.cur), true
+	}
+	s.cur, s.set = b, true
+	return s.values()
+}
+
+func (s *pivots) values() []Decimal {
+	return append([]Decimal(nil), s.levels...)
+}
+
+func (s *pivots) Len() int      { return 0 }
+func (s *pivots) LenAll() []int { return make([]int, 7) }
+func (s *pivots) Lookback() int { return 1 }
+func (s *pivots) Clone() Study  { return clone(s) }
+func (s *pivots) Reset()        { *s = *Pivots(s.method, s.session).(*pivots) }
+func (s *pivots) ready() bool   { return s.closed }
+
+func (s *pivots) ToStudy() (Study, bool)         { return s, true }
+func (s *pivots) ToMulti() (MultiVarStudy, bool) { return s, true }
@@ -0,0 +1,326 @@
+package ta
+
+import "go.oneofone.dev/ta/decimal"
+
+// TRange - True Range
+// the first value is high - low since there's no previous close
+func TRange() CandleStudy {
+	return &trange{}
+}
+
+var _ CandleStudy = (*trange)(nil)
+
+type trange struct {
+	prev Decimal
+	set  bool
+}
+
+func (s *trange) Update(vs ...Decimal) Decimal      { return s.update(barFromValues(vs)) }
+func (s *trange) UpdateAll(vs ...Decimal) []Decimal { return []Decimal{s.Update(vs...)} }
+func (s *trange) UpdateCandle(c *Candle) []Decimal  { return []Decimal{s.update(barFromCandle(c))} }
+
+func (s *trange) update(b bar) (tr Decimal) {
+	if tr = b.high - b.low; s.set {
+		tr = b.trueRange(s.prev)
+	}
+	s.prev, s.set = b.close, true
+	return tr
+}
+
+func (s *trange) Len() int      { return 0 }
+func (s *trange) LenAll() []int { return []int{0} }
+func (s *trange) Lookback() int { return 1 }
+func (s *trange) Clone() Study  { return clone(s) }
+func (s *trange) Reset()        { *s = trange{} }
+
+func (s *trange) ToStudy() (Study, bool)         { return s, true }
+func (s *trange) ToMulti() (MultiVarStudy, bool) { return s, true }
+
+// ATR - Average True Range
+// alias for ATRExt(period, WilderMA)
+func ATR(period int) CandleStudy {
+	return ATRExt(period, nil)
+}
+
+// ATRExt - Average True Range using the specified MA func to smooth the true range,
+// if ma is nil, WilderMA is used.
+func ATRExt(period int, ma MovingAverageFunc) CandleStudy {
+	return newATR(period, ma, false)
+}
+
+// NATR - Normalized Average True Range, ATR / close * 100
+func NATR(period int) CandleStudy {
+	return newATR(period, nil, true)
+}
+
+func newATR(period int, ma MovingAverageFunc, norm bool) *atr {
+	checkPeriod(period, 2)
+	if ma == nil {
+		ma = WilderMA
+	}
+	return &atr{
+		ma:     ma(period),
+		period: period,
+		norm:   norm,
+	}
+}
+
+var _ CandleStudy = (*atr)(nil)
+
+type atr struct {
+	tr     trange
+	ma     MovingAverage
+	last   Decimal
+	period int
+	norm   bool
+}
+
+func (s *atr) Update(vs ...Decimal) Decimal      { return s.update(barFromValues(vs)) }
+func (s *atr) UpdateAll(vs ...Decimal) []Decimal { return []Decimal{s.Update(vs...)} }
+func (s *atr) UpdateCandle(c *Candle) []Decimal  { return []Decimal{s.update(barFromCandle(c))} }
+
+func (s *atr) update(b bar) Decimal {
+	// the first bar doesn't have a previous close, so it doesn't count
+	first := !s.tr.set
+	tr := s.tr.update(b)
+	if first {
+		s.last = tr
+	} else {
+		s.last = s.ma.Update(tr)
+	}
+
+	if !s.norm {
+		return s.last
+	}
+	if b.close == 0 {
+		return 0
+	}
+	return s.last / b.close * 100
+}
+
+func (s *atr) Len() int      { return s.period }
+func (s *atr) LenAll() []int { return []int{s.period} }
+func (s *atr) Lookback() int { return 1 + Lookback(s.ma) }
+
+func (s *atr) Clone() Study { return clone(s) }
+
+func (s *atr) Reset() {
+	s.ma.Reset()
+	s.tr, s.last = trange{}, 0
+}
+
+func (s *atr) ToStudy() (Study, bool)         { return s, true }
+func (s *atr) ToMulti() (MultiVarStudy, bool) { return s, true }
+
+// KeltnerChannels returns a Keltner Channels study,
+// the mid line is ma(period) of the close and the bands are mid ± mult * ATR(atrPeriod),
+// if ma is nil, EMA is used.
+// ma only applies to the mid line, the ATR always uses Wilder's smoothing.
+// Update returns the upper band
+// UpdateAll returns [upper, mid, lower]
+func KeltnerChannels(period, atrPeriod int, mult Decimal, ma MovingAverageFunc) CandleStudy {
+	checkPeriod(period, 2)
+	if ma == nil {
+		ma = EMA
+	}
+	return &keltner{
+		ma:   ma(period),
+		atr:  newATR(atrPeriod, nil, false),
+		mult: mult,
+	}
+}
+
+var _ CandleStudy = (*keltner)(nil)
+
+type keltner struct {
+	ma   MovingAverage
+	atr  *atr
+	mult Decimal
+}
+
+func (s *keltner) Update(vs ...Decimal) Decimal      { return s.UpdateAll(vs...)[0] }
+func (s *keltner) UpdateAll(vs ...Decimal) []Decimal { return s.update(barFromValues(vs)) }
+func (s *keltner) UpdateCandle(c *Candle) []Decimal  { return s.update(barFromCandle(c)) }
+
+func (s *keltner) update(b bar) []Decimal {
+	mid := s.ma.Update(b.close)
+	d := s.atr.update(b) * s.mult
+	return []Decimal{mid + d, mid, mid - d}
+}
+
+func (s *keltner) Len() int      { return s.ma.Len() }
+func (s *keltner) Lookback() int { return decimal.Max(Lookback(s.ma), s.atr.Lookback()) }
+
+func (s *keltner) Clone() Study { return clone(s) }
+
+func (s *keltner) Reset() {
+	s.ma.Reset()
+	s.atr.Reset()
+}
+func (s *keltner) LenAll() []int {
+	ln := s.Len()
+	return []int{ln, ln, ln}
+}
+
+func (s *keltner) ToStudy() (Study, bool)         { return s, true }
+func (s *keltner) ToMulti() (MultiVarStudy, bool) { return s, true }
+
+// DonchianChannels returns the highest high and the lowest low over the period
+// Update returns the upper band
+// UpdateAll returns [upper, mid, lower]
+func DonchianChannels(period int) CandleStudy {
+	checkPeriod(period, 2)
+	return &donchian{
+		high: newRing(period),
+		low:  newRing(period),
+	}
+}
+
+var _ CandleStudy = (*donchian)(nil)
+
+type donchian struct {
+	high *TA
+	low  *TA
+}
+
+func (s *donchian) Update(vs ...Decimal) Decimal      { return s.UpdateAll(vs...)[0] }
+func (s *donchian) UpdateAll(vs ...Decimal) []Decimal { return s.update(barFromValues(vs)) }
+func (s *donchian) UpdateCandle(c *Candle) []Decimal  { return s.update(barFromCandle(c)) }
+
+func (s *donchian) update(b bar) []Decimal {
+	s.high.Update(b.high)
+	s.low.Update(b.low)
+	up, dn := s.high.Max(), s.low.Min()
+	return []Decimal{up, (up + dn) / 2, dn}
+}
+
+func (s *donchian) Len() int      { return s.high.Cap() }
+func (s *donchian) Lookback() int { return s.high.Cap() - 1 }
+func (s *donchian) Clone() Study  { return clone(s) }
+func (s *donchian) Reset()        { *s = *DonchianChannels(s.high.Cap()).(*donchian) }
+func (s *donchian) LenAll() []int {
+	ln := s.Len()
+	return []int{ln, ln, ln}
+}
+
+func (s *donchian) ToStudy() (Study, bool)         { return s, true }
+func (s *donchian) ToMulti() (MultiVarStudy, bool) { return s, true }
+
+// ChandelierExit returns the Chandelier Exit levels
+// long = highest high - mult * ATR(period) and short = lowest low + mult * ATR(period),
+// ma is used to smooth the true range, if nil, WilderMA is used.
+// Update returns the long exit
+// UpdateAll returns [long, short]
+func ChandelierExit(period int, mult Decimal, ma MovingAverageFunc) CandleStudy {
+	return &chandelier{
+		dc:   DonchianChannels(period).(*donchian),
+		atr:  newATR(period, ma, false),
+		mult: mult,
+	}
+}
+
+var _ CandleStudy = (*chandelier)(nil)
+
+type chandelier struct {
+	dc   *donchian
+	atr  *atr
+	mult Decimal
+}
+
+func (s *chandelier) Update(vs ...Decimal) Decimal      { return s.UpdateAll(vs...)[0] }
+func (s *chandelier) UpdateAll(vs ...Decimal) []Decimal { return s.update(barFromValues(vs)) }
+func (s *chandelier) UpdateCandle(c *Candle) []Decimal  { return s.update(barFromCandle(c)) }
+
+func (s *chandelier) update(b bar) []Decimal {
+	dc := s.dc.update(b)
+	d := s.atr.update(b) * s.mult
+	return []Decimal{dc[0] - d, dc[2] + d}
+}
+
+func (s *chandelier) Len() int      { return s.atr.Len() }
+func (s *chandelier) LenAll() []int { return []int{s.Len(), s.Len()} }
+func (s *chandelier) Lookback() int { return decimal.Max(s.dc.Lookback(), s.atr.Lookback()) }
+
+func (s *chandelier) Clone() Study { return clone(s) }
+
+func (s *chandelier) Reset() {
+	s.dc.Reset()
+	s.atr.Reset()
+}
+
+func (s *chandelier) ToStudy() (Study, bool)         { return s, true }
+func (s *chandelier) ToMulti() (MultiVarStudy, bool) { return s, true }
+
+// SuperTrend returns a SuperTrend study, the bands are hl2 ± mult * ATR(period),
+// ma is used to smooth the true range, if nil, WilderMA is used.
+// Update returns the SuperTrend line
+// UpdateAll returns [line, direction], direction is 1 for an up trend and -1 for a down trend
+func SuperTrend(period int, mult Decimal, ma MovingAverageFunc) CandleStudy {
+	return &supertrend{
+		atr:  newATR(period, ma, false),
+		mult: mult,
+		dir:  1,
+	}
+}
+
+var _ CandleStudy = (*supertrend)(nil)
+
+type supertrend struct {
+	atr       *atr
+	mult      Decimal
+	up        Decimal
+	down      Decimal
+	prevClose Decimal
+	dir       Decimal
+	set       bool
+}
+
+func (s *supertrend) Update(vs ...Decimal) Decimal      { return s.UpdateAll(vs...)[0] }
+func (s *supertrend) UpdateAll(vs ...Decimal) []Decimal { return s.update(barFromValues(vs)) }
+func (s *supertrend) UpdateCandle(c *Candle) []Decimal  { return s.update(barFromCandle(c)) }
+
+func (s *supertrend) update(b bar) []Decimal {
+	var (
+		d    = s.atr.update(b) * s.mult
+		mid  = b.hl2()
+		up   = mid - d
+		down = mid + d
+	)
+
+	if s.set {
+		if s.prevClose > s.up && up < s.up {
+			up = s.up
+		}
+		if s.prevClose < s.down && down > s.down {
+			down = s.down
+		}
+
+		switch {
+		case s.dir < 0 && b.close > s.down:
+			s.dir = 1
+		case s.dir > 0 && b.close < s.up:
+			s.dir = -1
+		}
+	}
+
+	s.up, s.down, s.prevClose, s.set = up, down, b.close, true
+
+	if s.dir > 0 {
+		return []Decimal{up, s.dir}
+	}
+	return []Decimal{down, s.dir}
+}
+
+func (s *supertrend) Len() int      { return s.atr.Len() }
+func (s *supertrend) LenAll() []int { return []int{s.Len(), s.Len()} }
+func (s *supertrend) Lookback() int { return s.atr.Lookback() }
+
+func (s *supertrend) Clone() Study { return clone(s) }
+
+func (s *supertrend) Reset() {
+	s.atr.Reset()
+	*s = supertrend{atr: s.atr, mult: s.mult, dir: 1}
+}
+
+func (s *supertrend) ToStudy() (Study, bool)         { return s, true }
+func (s *supertrend) ToMulti() (MultiVarStudy, bool) { return s, true }
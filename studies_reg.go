@@ -0,0 +1,218 @@
+package ta
+
+const (
+	regLine uint8 = iota
+	regSlope
+	regIntercept
+	regAngle
+	regTSF
+)
+
+// LinearReg - Linear Regression, returns the value of the least-squares line at the last bar
+// UpdateAll returns [linreg, slope, intercept, angle, tsf]
+func LinearReg(period int) MultiVarStudy {
+	return newLinReg(period, regLine)
+}
+
+// LinearRegSlope - Linear Regression Slope, same as LinearReg, except Update returns the slope
+func LinearRegSlope(period int) MultiVarStudy {
+	return newLinReg(period, regSlope)
+}
+
+// LinearRegIntercept - Linear Regression Intercept, same as LinearReg, except Update returns the intercept
+func LinearRegIntercept(period int) MultiVarStudy {
+	return newLinReg(period, regIntercept)
+}
+
+// LinearRegAngle - Linear Regression Angle in degrees, same as LinearReg, except Update returns the angle
+func LinearRegAngle(period int) MultiVarStudy {
+	return newLinReg(period, regAngle)
+}
+
+// TSF - Time Series Forecast, same as LinearReg, except Update returns the value of the line projected to the next bar
+func TSF(period int) MultiVarStudy {
+	return newLinReg(period, regTSF)
+}
+
+func newLinReg(period int, mode uint8) *linreg {
+	checkPeriod(period, 2)
+	n := Decimal(period)
+	sumX := n * (n - 1) * 0.5
+	sumXX := n * (n - 1) * (n*2 - 1) / 6
+	return &linreg{
+		data:    NewCapped(period),
+		sumX:    sumX,
+		divisor: sumX*sumX - n*sumXX,
+		period:  period,
+		mode:    mode,
+	}
+}
+
+var _ MultiVarStudy = (*linreg)(nil)
+
+// linreg follows TA-Lib's formulas where x is the age of the value (0 is the last one),
+// sumXY is updated in O(1) since every value ages by one bar on each update.
+type linreg struct {
+	data    *TA
+	sumX    Decimal
+	divisor Decimal
+	sumY    Decimal
+	sumXY   Decimal
+	period  int
+	mode    uint8
+}
+
+func (s *linreg) Update(vs ...Decimal) Decimal {
+	return s.UpdateAll(vs...)[s.mode]
+}
+
+func (s *linreg) UpdateAll(vs ...Decimal) []Decimal {
+	n := Decimal(s.period)
+	for _, v := range vs {
+		old := s.data.Update(v)
+		s.sumXY += s.sumY - n*old
+		s.sumY += v - old
+	}
+
+	m := (n*s.sumXY - s.sumX*s.sumY) / s.divisor
+	b := (s.sumY - m*s.sumX) / n
+	return []Decimal{
+		b + m*(n-1),
+		m,
+		b,
+		m.Atan() * rad2Deg,
+		b + m*n,
+	}
+}
+
+func (s *linreg) Len() int      { return s.period }
+func (s *linreg) Lookback() int { return s.period - 1 }
+func (s *linreg) Clone() Study  { return clone(s) }
+func (s *linreg) Reset()        { *s = *newLinReg(s.period, s.mode) }
+func (s *linreg) LenAll() []int {
+	ln := s.period
+	return []int{ln, ln, ln, ln, ln}
+}
+
+func (s *linreg) ToStudy() (Study, bool)         { return s, true }
+func (s *linreg) ToMulti() (MultiVarStudy, bool) { return s, true }
+
+// Beta - rolling Beta of y relative to x over the returns of the last period bars, same as TA-Lib's BETA,
+// e.g. x is the index (SPY) and y is the symbol.
+// Update/UpdateAll expects 2 values, x and y, it will panic otherwise
+func Beta(period int) Study {
+	checkPeriod(period, 1)
+	return &beta{
+		x:      NewCapped(period),
+		y:      NewCapped(period),
+		period: period,
+	}
+}
+
+var _ Study = (*beta)(nil)
+
+type beta struct {
+	x, y         *TA
+	prevX, prevY Decimal
+	sx, sy       Decimal
+	sxx, sxy     Decimal
+	period       int
+	set          bool
+}
+
+func (s *beta) Update(vs ...Decimal) Decimal {
+	if len(vs) != 2 {
+		panic("beta: must provide x and y")
+	}
+
+	x, y := vs[0], vs[1]
+	// the first bar doesn't have a previous value, so it doesn't count
+	if !s.set {
+		s.prevX, s.prevY, s.set = x, y, true
+		return 0
+	}
+
+	var rx, ry Decimal
+	if s.prevX != 0 {
+		rx = (x - s.prevX) / s.prevX
+	}
+	if s.prevY != 0 {
+		ry = (y - s.prevY) / s.prevY
+	}
+	s.prevX, s.prevY = x, y
+
+	ox, oy := s.x.Update(rx), s.y.Update(ry)
+	s.sx += rx - ox
+	s.sy += ry - oy
+	s.sxx += rx*rx - ox*ox
+	s.sxy += rx*ry - ox*oy
+
+	n := Decimal(s.period)
+	if d := n*s.sxx - s.sx*s.sx; !isZero(d) {
+		return (n*s.sxy - s.sx*s.sy) / d
+	}
+	return 0
+}
+
+func (s *beta) UpdateAll(vs ...Decimal) []Decimal { return []Decimal{s.Update(vs...)} }
+
+func (s *beta) Len() int      { return s.period }
+func (s *beta) LenAll() []int { return []int{s.period} }
+func (s *beta) Lookback() int { return s.period }
+func (s *beta) Clone() Study  { return clone(s) }
+func (s *beta) Reset()        { *s = *Beta(s.period).(*beta) }
+
+func (s *beta) ToStudy() (Study, bool)         { return s, true }
+func (s *beta) ToMulti() (MultiVarStudy, bool) { return s, true }
+
+// Correl - rolling Pearson's Correlation Coefficient of x and y, same as TA-Lib's CORREL
+// Update/UpdateAll expects 2 values, x and y, it will panic otherwise
+func Correl(period int) Study {
+	checkPeriod(period, 2)
+	return &correl{
+		x:      NewCapped(period),
+		y:      NewCapped(period),
+		period: period,
+	}
+}
+
+var _ Study = (*correl)(nil)
+
+type correl struct {
+	x, y          *TA
+	sx, sy        Decimal
+	sxx, syy, sxy Decimal
+	period        int
+}
+
+func (s *correl) Update(vs ...Decimal) Decimal {
+	if len(vs) != 2 {
+		panic("correl: must provide x and y")
+	}
+
+	x, y := vs[0], vs[1]
+	ox, oy := s.x.Update(x), s.y.Update(y)
+	s.sx += x - ox
+	s.sy += y - oy
+	s.sxx += x*x - ox*ox
+	s.syy += y*y - oy*oy
+	s.sxy += x*y - ox*oy
+
+	n := Decimal(s.period)
+	// same as TA_IS_ZERO_OR_NEG
+	if d := (s.sxx - s.sx*s.sx/n) * (s.syy - s.sy*s.sy/n); d >= 1e-14 {
+		return (s.sxy - s.sx*s.sy/n) / d.Sqrt()
+	}
+	return 0
+}
+
+func (s *correl) UpdateAll(vs ...Decimal) []Decimal { return []Decimal{s.Update(vs...)} }
+
+func (s *correl) Len() int      { return s.period }
+func (s *correl) LenAll() []int { return []int{s.period} }
+func (s *correl) Lookback() int { return s.period - 1 }
+func (s *correl) Clone() Study  { return clone(s) }
+func (s *correl) Reset()        { *s = *Correl(s.period).(*correl) }
+
+func (s *correl) ToStudy() (Study, bool)         { return s, true }
+func (s *correl) ToMulti() (MultiVarStudy, bool) { return s, true }
@@ -0,0 +1,97 @@
+package ta
+
+// RevisableStudy is a study that supports intrabar updates, the forming bar can be revised
+// any number of times before it's closed, each revision replaces the previous one instead of adding a new bar.
+type RevisableStudy interface {
+	MultiVarStudy
+
+	// Revise updates the forming bar with vs and returns the same values as UpdateAll,
+	// without advancing the study.
+	Revise(vs ...Decimal) []Decimal
+
+	// Commit closes the forming bar with the values of the last Revise call
+	Commit()
+}
+
+// Revisable returns a revisable version of s, the state before the forming bar is kept as a clone of s.
+// Update and UpdateAll close the forming bar, replacing any pending revision, so N revisions followed by Commit
+// or by an Update with the final value return the same as a single Update with the final value.
+func Revisable(s Study) RevisableStudy {
+	rs := &revised{base: s.Clone()}
+	rs.set(s)
+	return rs
+}
+
+var _ RevisableStudy = (*revised)(nil)
+
+type revised struct {
+	s Study
+	m MultiVarStudy
+
+	// base is the state before the forming bar, it's only valid while a revision is pending,
+	// it's never nil so a snapshot with a pending revision can be restored into it
+	base    Study
+	pending bool
+}
+
+func (s *revised) set(st Study) {
+	s.s = st
+	s.m, _ = st.ToMulti()
+}
+
+// begin restores the state before the forming bar if needed, if revise is true, the state is saved
+// so the next call can undo the update.
+func (s *revised) begin(revise bool) {
+	switch {
+	case s.pending:
+		s.set(s.base.Clone())
+	case revise:
+		s.base = s.s.Clone()
+	}
+	s.pending = revise
+}
+
+func (s *revised) Update(vs ...Decimal) Decimal {
+	s.begin(false)
+	return s.s.Update(vs...)
+}
+
+func (s *revised) UpdateAll(vs ...Decimal) []Decimal {
+	s.begin(false)
+	return s.all(vs)
+}
+
+func (s *revised) Revise(vs ...Decimal) []Decimal {
+	s.begin(true)
+	return s.all(vs)
+}
+
+func (s *revised) Commit() { s.pending = false }
+
+func (s *revised) all(vs []Decimal) []Decimal {
+	if s.m != nil {
+		return s.m.UpdateAll(vs...)
+	}
+	return []Decimal{s.s.Update(vs...)}
+}
+
+func (s *revised) Len() int      { return s.s.Len() }
+func (s *revised) Lookback() int { return Lookback(s.s) }
+
+func (s *revised) Clone() Study { return clone(s) }
+
+func (s *revised) Reset() {
+	s.s.Reset()
+	s.base.Reset()
+	s.pending = false
+}
+
+func (s *revised) LenAll() []int {
+	if s.m != nil {
+		return s.m.LenAll()
+	}
+	return []int{s.s.Len()}
+}
+
+func (s *revised) ToStudy() (Study, bool)         { return s, true }
+func (s *revised) ToMulti() (MultiVarStudy, bool) { return s, true }
@@ -0,0 +1,175 @@
+package ta
+
+import "go.oneofone.dev/ta/decimal"
+
+// SAR - Parabolic SAR, uses the high and low of every candle
+// the first candle is only used to pick the initial direction.
+// Update returns the SAR
+// UpdateAll returns [SAR, direction], direction is 1 for long and -1 for short
+func SAR(accel, max Decimal) CandleStudy {
+	return SARExt(0, 0, accel, accel, max, accel, accel, max)
+}
+
+// SARExt - Parabolic SAR Extended, same as TA-Lib's SAREXT
+// if startValue is 0, the initial direction is picked the same way as SAR,
+// otherwise a positive value starts long and a negative one starts short at |startValue|.
+// offsetOnReverse is added (as a percent of the SAR) to the SAR on reversals.
+// unlike TA-Lib, the SAR is always positive, the direction is returned by UpdateAll.
+// Update returns the SAR
+// UpdateAll returns [SAR, direction], direction is 1 for long and -1 for short
+func SARExt(startValue, offsetOnReverse, afInitLong, afLong, afMaxLong, afInitShort, afShort, afMaxShort Decimal) CandleStudy {
+	if afMaxLong <= 0 || afMaxShort <= 0 {
+		panic("sar: the max acceleration factors must be > 0")
+	}
+	return &sar{
+		start:    startValue,
+		offset:   offsetOnReverse,
+		initLong: decimal.Min(afInitLong, afMaxLong),
+		stepLong: decimal.Min(afLong, afMaxLong),
+		maxLong:  afMaxLong,
+
+		initShort: decimal.Min(afInitShort, afMaxShort),
+		stepShort: decimal.Min(afShort, afMaxShort),
+		maxShort:  afMaxShort,
+	}
+}
+
+var _ CandleStudy = (*sar)(nil)
+
+type sar struct {
+	start, offset                  Decimal
+	initLong, stepLong, maxLong    Decimal
+	initShort, stepShort, maxShort Decimal
+
+	afLong, afShort Decimal
+	sar, ep         Decimal
+	high, low       Decimal
+	out             Decimal
+
+	count int
+	long  bool
+}
+
+func (s *sar) Update(vs ...Decimal) Decimal      { return s.UpdateAll(vs...)[0] }
+func (s *sar) UpdateAll(vs ...Decimal) []Decimal { return s.update(barFromValues(vs)) }
+func (s *sar) UpdateCandle(c *Candle) []Decimal  { return s.update(barFromCandle(c)) }
+
+func (s *sar) update(b bar) []Decimal {
+	switch s.count++; s.count {
+	case 1:
+		s.high, s.low = b.high, b.low
+		return []Decimal{0, 0}
+	case 2:
+		s.init(b)
+	}
+
+	prevHigh, prevLow := s.high, s.low
+	s.high, s.low = b.high, b.low
+
+	if s.long {
+		if s.low <= s.sar {
+			// switch to short
+			s.long, s.sar = false, s.ep
+			s.sar = decimal.Max(s.sar, prevHigh, s.high)
+			if s.offset != 0 {
+				s.sar += s.sar * s.offset
+			}
+			s.out = s.sar
+
+			s.afShort, s.ep = s.initShort, s.low
+			s.sar += s.afShort * (s.ep - s.sar)
+			s.sar = decimal.Max(s.sar, prevHigh, s.high)
+		} else {
+			s.out = s.sar
+
+			if s.high > s.ep {
+				s.ep = s.high
+				s.afLong = decimal.Min(s.afLong+s.stepLong, s.maxLong)
+			}
+			s.sar += s.afLong * (s.ep - s.sar)
+			s.sar = decimal.Min(s.sar, prevLow, s.low)
+		}
+	} else {
+		if s.high >= s.sar {
+			// switch to long
+			s.long, s.sar = true, s.ep
+			s.sar = decimal.Min(s.sar, prevLow, s.low)
+			if s.offset != 0 {
+				s.sar -= s.sar * s.offset
+			}
+			s.out = s.sar
+
+			s.afLong, s.ep = s.initLong, s.high
+			s.sar += s.afLong * (s.ep - s.sar)
+			s.sar = decimal.Min(s.sar, prevLow, s.low)
+		} else {
+			s.out = s.sar
+
+			if s.low < s.ep {
+				s.ep = s.low
+				s.afShort = decimal.Min(s.afShort+s.stepShort, s.maxShort)
+			}
+			s.sar += s.afShort * (s.ep - s.sar)
+			s.sar = decimal.Max(s.sar, prevHigh, s.high)
+		}
+	}
+
+	return []Decimal{s.out, s.dir()}
+}
+
+// init picks the initial direction and SAR using the first 2 candles, same as TA-Lib
+func (s *sar) init(b bar) {
+	s.afLong, s.afShort = s.initLong, s.initShort
+
+	switch {
+	case s.start == 0:
+		// start short if there's a -DM between the first 2 candles
+		diffP, diffM := b.high-s.high, s.low-b.low
+		s.long = !(diffM > 0 && diffP < diffM)
+		if s.long {
+			s.sar = s.low
+		} else {
+			s.sar = s.high
+		}
+	case s.start > 0:
+		s.long, s.sar = true, s.start
+	default:
+		s.long, s.sar = false, -s.start
+	}
+
+	if s.long {
+		s.ep = b.high
+	} else {
+		s.ep = b.low
+	}
+
+	// TA-Lib uses the 2nd candle as the previous candle on the first update
+	s.high, s.low = b.high, b.low
+}
+
+// dir returns 1 if the SAR is long, -1 if it's short and 0 before the first SAR
+func (s *sar) dir() Decimal {
+	switch {
+	case s.count < 2:
+		return 0
+	case s.long:
+		return 1
+	default:
+		return -1
+	}
+}
+
+func (s *sar) Len() int      { return 0 }
+func (s *sar) LenAll() []int { return []int{0, 0} }
+func (s *sar) Lookback() int { return 1 }
+
+func (s *sar) Clone() Study { return clone(s) }
+
+func (s *sar) Reset() {
+	s.afLong, s.afShort = 0, 0
+	s.sar, s.ep, s.high, s.low, s.out = 0, 0, 0, 0, 0
+	s.count, s.long = 0, false
+}
+
+func (s *sar) ToStudy() (Study, bool)         { return s, true }
+func (s *sar) ToMulti() (MultiVarStudy, bool) { return s, true }
@@ -0,0 +1,686 @@
+package ta
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"time"
+	"unsafe"
+)
+
+// StateVersion is the version of the snapshots created by MarshalStudy and MarshalStudyJSON,
+// snapshots with a newer version are rejected.
+// The state is stored by field name, a snapshot with missing or unknown fields is rejected
+// unless a migration for its version converts it first.
+const StateVersion = 1
+
+var (
+	// ErrStateVersion is returned when restoring a snapshot created by a newer version of the library
+	ErrStateVersion = errors.New("ta: unsupported snapshot version")
+	// ErrStateType is returned when a snapshot doesn't match the study it's restored into
+	ErrStateType = errors.New("ta: snapshot doesn't match the study")
+	// ErrStateFormat is returned for invalid snapshots
+	ErrStateFormat = errors.New("ta: invalid snapshot")
+)
+
+const stateMagic = "TAST"
+
+var (
+	timeType = reflect.TypeOf(time.Time{})
+	taType   = reflect.TypeOf(TA{})
+)
+
+// stateMigrations converts the study state of a version v snapshot to version v+1,
+// they're needed when a new version renames, adds or removes state fields.
+var stateMigrations = map[int64]func(st interface{}) (interface{}, error){}
+
+// stateLocker is implemented by studies that guard their state with a mutex,
+// lockState locks it while the state is read or restored and returns the func that unlocks it.
+type stateLocker interface {
+	lockState() (unlock func())
+}
+
+// MarshalStudy returns a binary snapshot of the state of s, including any nested studies
+func MarshalStudy(s Study) ([]byte, error) {
+	v, err := studyState(s)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	buf.WriteString(stateMagic)
+	writeState(&buf, v)
+	return buf.Bytes(), nil
+}
+
+// MarshalStudyJSON same as MarshalStudy, except the snapshot is JSON
+func MarshalStudyJSON(s Study) ([]byte, error) {
+	v, err := studyState(s)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(jsonState(v))
+}
+
+// UnmarshalStudy restores a snapshot created by MarshalStudy or MarshalStudyJSON into s,
+// s must be created with the same constructor and parameters as the study the snapshot was taken from,
+// otherwise ErrStateType is returned and s isn't changed.
+// Funcs, like the MovingAverageFunc of a study, aren't part of the state.
+// Once restored, s returns the same values as the original study would.
+func UnmarshalStudy(s Study, data []byte) (err error) {
+	var v interface{}
+	if bytes.HasPrefix(data, []byte(stateMagic)) {
+		r := bytes.NewReader(data[len(stateMagic):])
+		if v, err = readState(r); err == nil && r.Len() > 0 {
+			err = ErrStateFormat
+		}
+	} else {
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.UseNumber()
+		err = dec.Decode(&v)
+	}
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrStateFormat, err)
+	}
+
+	m, _ := v.(map[string]interface{})
+	ver, err := stateInt(m["version"])
+	switch {
+	case err != nil:
+		return fmt.Errorf("%w: missing version", ErrStateFormat)
+	case ver > StateVersion || ver < 1:
+		return fmt.Errorf("%w: %d", ErrStateVersion, ver)
+	case m["config"] == nil:
+		return fmt.Errorf("%w: missing config", ErrStateFormat)
+	}
+
+	cfg, err := configState(s)
+	if err != nil {
+		return err
+	}
+	if diff := diffState(normState(cfg), normState(m["config"]), "study"); diff != "" {
+		return fmt.Errorf("%w: %s", ErrStateType, diff)
+	}
+
+	st := m["study"]
+	for ; ver < StateVersion; ver++ {
+		migrate := stateMigrations[ver]
+		if migrate == nil {
+			return fmt.Errorf("%w: %d", ErrStateVersion, ver)
+		}
+		if st, err = migrate(st); err != nil {
+			return fmt.Errorf("%w: %v", ErrStateFormat, err)
+		}
+	}
+
+	// restore into a clone first, so s isn't changed if the state doesn't match
+	for _, s := range []Study{s.Clone(), s} {
+		if err = (&stateDecoder{}).decode(reflect.ValueOf(&s).Elem(), st, "study"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func studyState(s Study) (interface{}, error) {
+	st, err := (&stateEncoder{seen: map[uintptr]bool{}}).encode(reflect.ValueOf(&s).Elem())
+	if err != nil {
+		return nil, err
+	}
+	cfg, err := configState(s)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{"version": int64(StateVersion), "config": cfg, "study": st}, nil
+}
+
+// configState returns the state of a reset clone of s, two studies created with the same constructor and parameters
+// have the same config state
+func configState(s Study) (interface{}, error) {
+	c := s.Clone()
+	c.Reset()
+	return (&stateEncoder{seen: map[uintptr]bool{}, config: true}).encode(reflect.ValueOf(&c).Elem())
+}
+
+// stateEncoder converts a value to a tree of nil, bool, int64, float64, string, []interface{} and map[string]interface{}
+type stateEncoder struct {
+	seen   map[uintptr]bool // the pointers on the current path, to detect cycles
+	config bool             // only the size of a TA is stored, not its values
+}
+
+func (e *stateEncoder) encode(v reflect.Value) (interface{}, error) {
+	switch v.Kind() {
+	case reflect.Bool:
+		return v.Bool(), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int(), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return int64(v.Uint()), nil
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), nil
+	case reflect.String:
+		return v.String(), nil
+
+	case reflect.Interface:
+		if v.IsNil() {
+			return nil, nil
+		}
+		st, err := e.encode(v.Elem())
+		if err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{"type": v.Elem().Type().String(), "state": st}, nil
+
+	case reflect.Ptr:
+		if v.IsNil() {
+			return nil, nil
+		}
+		p := v.Pointer()
+		if e.seen[p] {
+			return nil, fmt.Errorf("ta: cycle in %s", v.Type())
+		}
+		e.seen[p] = true
+		defer delete(e.seen, p)
+		if v.Type().Elem() == taType {
+			ta := v.Interface().(*TA)
+			if e.config {
+				return map[string]interface{}{"len": int64(len(ta.v)), "cap": int64(cap(ta.v))}, nil
+			}
+			return encodeTA(ta), nil
+		}
+		if l, ok := v.Interface().(stateLocker); ok {
+			defer l.lockState()()
+		}
+		return e.encode(v.Elem())
+
+	case reflect.Slice, reflect.Array:
+		out := make([]interface{}, 0, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			iv, err := e.encode(v.Index(i))
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, iv)
+		}
+		return out, nil
+
+	case reflect.Struct:
+		if v.Type() == timeType {
+			b, err := exported(v).Interface().(time.Time).MarshalText()
+			return string(b), err
+		}
+		out := map[string]interface{}{}
+		for i := 0; i < v.NumField(); i++ {
+			f := v.Type().Field(i)
+			if skipStateField(f.Type) {
+				continue
+			}
+			fv, err := e.encode(exported(v.Field(i)))
+			if err != nil {
+				return nil, fmt.Errorf("%s.%s: %w", v.Type(), f.Name, err)
+			}
+			out[f.Name] = fv
+		}
+		return out, nil
+
+	default:
+		return nil, fmt.Errorf("ta: can't snapshot %s", v.Type())
+	}
+}
+
+func encodeTA(ta *TA) interface{} {
+	vs := make([]interface{}, 0, len(ta.v))
+	for _, v := range ta.v {
+		vs = append(vs, float64(v))
+	}
+	out := map[string]interface{}{"v": vs, "cap": int64(cap(ta.v))}
+	if ta.idx != nil {
+		out["idx"] = int64(*ta.idx)
+	}
+	return out
+}
+
+type stateDecoder struct{}
+
+func (d *stateDecoder) decode(v reflect.Value, st interface{}, path string) (err error) {
+	fail := func(msg string, args ...interface{}) error {
+		return fmt.Errorf("%w: %s: %s", ErrStateType, path, fmt.Sprintf(msg, args...))
+	}
+
+	switch v.Kind() {
+	case reflect.Bool:
+		b, ok := st.(bool)
+		if !ok {
+			return fail("expected a bool")
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := stateInt(st)
+		if err != nil {
+			return fail("%v", err)
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		n, err := stateInt(st)
+		if err != nil {
+			return fail("%v", err)
+		}
+		v.SetUint(uint64(n))
+	case reflect.Float32, reflect.Float64:
+		f, err := stateFloat(st)
+		if err != nil {
+			return fail("%v", err)
+		}
+		v.SetFloat(f)
+	case reflect.String:
+		s, ok := st.(string)
+		if !ok {
+			return fail("expected a string")
+		}
+		v.SetString(s)
+
+	case reflect.Interface:
+		if st == nil {
+			v.Set(reflect.Zero(v.Type()))
+			return nil
+		}
+		m, _ := st.(map[string]interface{})
+		if v.IsNil() {
+			return fail("can't restore %v into a nil %s", m["type"], v.Type())
+		}
+		cur := v.Elem()
+		if typ := cur.Type().String(); m["type"] != typ {
+			return fail("expected %s, got %v", typ, m["type"])
+		}
+		if cur.Kind() == reflect.Ptr {
+			return d.decode(cur, m["state"], path)
+		}
+		// values stored in an interface aren't addressable
+		nv := reflect.New(cur.Type()).Elem()
+		nv.Set(cur)
+		if err = d.decode(nv, m["state"], path); err == nil {
+			v.Set(nv)
+		}
+		return err
+
+	case reflect.Ptr:
+		if st == nil {
+			v.Set(reflect.Zero(v.Type()))
+			return nil
+		}
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		if v.Type().Elem() == taType {
+			return d.decodeTA(v.Interface().(*TA), st, path)
+		}
+		if l, ok := v.Interface().(stateLocker); ok {
+			defer l.lockState()()
+		}
+		return d.decode(v.Elem(), st, path)
+
+	case reflect.Slice, reflect.Array:
+		items, ok := st.([]interface{})
+		if !ok && st != nil {
+			return fail("expected a list")
+		}
+		if v.Kind() == reflect.Array && len(items) != v.Len() {
+			return fail("expected %d items, got %d", v.Len(), len(items))
+		}
+		if v.Kind() == reflect.Slice && len(items) != v.Len() {
+			// keep the existing items, they may hold studies that can't be created from the state
+			nv := reflect.MakeSlice(v.Type(), len(items), len(items))
+			reflect.Copy(nv, v)
+			v.Set(nv)
+		}
+		for i, it := range items {
+			if err = d.decode(v.Index(i), it, path+"["+strconv.Itoa(i)+"]"); err != nil {
+				return err
+			}
+		}
+
+	case reflect.Struct:
+		if v.Type() == timeType {
+			s, ok := st.(string)
+			if !ok {
+				return fail("expected a time")
+			}
+			var t time.Time
+			if err = t.UnmarshalText([]byte(s)); err != nil {
+				return fail("%v", err)
+			}
+			exported(v).Set(reflect.ValueOf(t))
+			return nil
+		}
+		m, ok := st.(map[string]interface{})
+		if !ok {
+			return fail("expected a struct")
+		}
+		fields := map[string]bool{}
+		for i := 0; i < v.NumField(); i++ {
+			f := v.Type().Field(i)
+			if skipStateField(f.Type) {
+				continue
+			}
+			fs, ok := m[f.Name]
+			if !ok {
+				return fail("missing field %s", f.Name)
+			}
+			fields[f.Name] = true
+			if err = d.decode(exported(v.Field(i)), fs, path+"."+f.Name); err != nil {
+				return err
+			}
+		}
+		if len(fields) != len(m) {
+			for _, k := range sortedKeys(m) {
+				if !fields[k] {
+					return fail("unknown field %s", k)
+				}
+			}
+		}
+
+	default:
+		return fail("can't restore %s", v.Type())
+	}
+	return nil
+}
+
+func (d *stateDecoder) decodeTA(ta *TA, st interface{}, path string) error {
+	m, _ := st.(map[string]interface{})
+	vs, ok := m["v"].([]interface{})
+	c, err := stateInt(m["cap"])
+	if !ok || err != nil || int(c) < len(vs) {
+		return fmt.Errorf("%w: %s: invalid TA", ErrStateType, path)
+	}
+
+	ta.v = make([]Decimal, len(vs), c)
+	for i, v := range vs {
+		f, err := stateFloat(v)
+		if err != nil {
+			return fmt.Errorf("%w: %s: %v", ErrStateType, path, err)
+		}
+		ta.v[i] = Decimal(f)
+	}
+
+	if m["idx"] == nil {
+		ta.idx = nil
+		return nil
+	}
+	idx, err := stateInt(m["idx"])
+	if err != nil {
+		return fmt.Errorf("%w: %s: %v", ErrStateType, path, err)
+	}
+	if ta.idx == nil {
+		ta.idx = new(int)
+	}
+	*ta.idx = int(idx)
+	return nil
+}
+
+// skipStateField returns true for fields that are part of the configuration of a study rather than its state,
+// and for empty fields, like the embedded noMulti
+func skipStateField(t reflect.Type) bool {
+	if t.Size() == 0 {
+		return true
+	}
+	switch t.Kind() {
+	case reflect.Func, reflect.Chan, reflect.UnsafePointer:
+		return true
+	}
+	return t.PkgPath() == "sync"
+}
+
+// exported returns a settable version of v, which must be addressable
+func exported(v reflect.Value) reflect.Value {
+	if v.CanSet() {
+		return v
+	}
+	return reflect.NewAt(v.Type(), unsafe.Pointer(v.UnsafeAddr())).Elem()
+}
+
+// normState converts the numbers of a state tree to strings, so trees decoded from JSON and binary can be compared
+func normState(v interface{}) interface{} {
+	switch v := v.(type) {
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case float64:
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	case json.Number:
+		return v.String()
+	case []interface{}:
+		out := make([]interface{}, len(v))
+		for i, it := range v {
+			out[i] = normState(it)
+		}
+		return out
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(v))
+		for k, it := range v {
+			out[k] = normState(it)
+		}
+		return out
+	}
+	return v
+}
+
+// diffState returns a description of the first difference between the normalized state trees of the study (exp)
+// and of the snapshot (got), or an empty string if they're the same
+func diffState(exp, got interface{}, path string) string {
+	switch exp := exp.(type) {
+	case map[string]interface{}:
+		got, ok := got.(map[string]interface{})
+		if !ok {
+			break
+		}
+		for _, k := range sortedKeys(exp) {
+			if _, ok := got[k]; !ok {
+				return fmt.Sprintf("%s.%s: missing from the snapshot", path, k)
+			}
+			if d := diffState(exp[k], got[k], path+"."+k); d != "" {
+				return d
+			}
+		}
+		for _, k := range sortedKeys(got) {
+			if _, ok := exp[k]; !ok {
+				return fmt.Sprintf("%s.%s: unknown field", path, k)
+			}
+		}
+		return ""
+
+	case []interface{}:
+		got, ok := got.([]interface{})
+		if !ok {
+			break
+		}
+		if len(exp) != len(got) {
+			return fmt.Sprintf("%s: the study has %d items, the snapshot has %d", path, len(exp), len(got))
+		}
+		for i := range exp {
+			if d := diffState(exp[i], got[i], path+"["+strconv.Itoa(i)+"]"); d != "" {
+				return d
+			}
+		}
+		return ""
+	}
+
+	if !reflect.DeepEqual(exp, got) {
+		return fmt.Sprintf("%s: the study has %v, the snapshot has %v", path, exp, got)
+	}
+	return ""
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func stateInt(v interface{}) (int64, error) {
+	switch v := v.(type) {
+	case int64:
+		return v, nil
+	case json.Number:
+		return v.Int64()
+	}
+	return 0, fmt.Errorf("expected an int, got %T", v)
+}
+
+func stateFloat(v interface{}) (float64, error) {
+	switch v := v.(type) {
+	case float64:
+		return v, nil
+	case string:
+		return strconv.ParseFloat(v, 64)
+	case json.Number:
+		return v.Float64()
+	}
+	return 0, fmt.Errorf("expected a float, got %T", v)
+}
+
+// jsonState converts floats to strings, so NaN and Inf survive and the values are bit-identical
+func jsonState(v interface{}) interface{} {
+	switch v := v.(type) {
+	case float64:
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	case []interface{}:
+		out := make([]interface{}, len(v))
+		for i, it := range v {
+			out[i] = jsonState(it)
+		}
+		return out
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(v))
+		for k, it := range v {
+			out[k] = jsonState(it)
+		}
+		return out
+	}
+	return v
+}
+
+// binary state tags
+const (
+	stateNil byte = iota
+	stateFalse
+	stateTrue
+	stateIntTag
+	stateFloatTag
+	stateString
+	stateList
+	stateMap
+)
+
+func writeState(w *bytes.Buffer, v interface{}) {
+	var buf [binary.MaxVarintLen64]byte
+	uvarint := func(n uint64) { w.Write(buf[:binary.PutUvarint(buf[:], n)]) }
+	str := func(s string) {
+		uvarint(uint64(len(s)))
+		w.WriteString(s)
+	}
+
+	switch v := v.(type) {
+	case nil:
+		w.WriteByte(stateNil)
+	case bool:
+		if v {
+			w.WriteByte(stateTrue)
+		} else {
+			w.WriteByte(stateFalse)
+		}
+	case int64:
+		w.WriteByte(stateIntTag)
+		w.Write(buf[:binary.PutVarint(buf[:], v)])
+	case float64:
+		w.WriteByte(stateFloatTag)
+		binary.LittleEndian.PutUint64(buf[:8], math.Float64bits(v))
+		w.Write(buf[:8])
+	case string:
+		w.WriteByte(stateString)
+		str(v)
+	case []interface{}:
+		w.WriteByte(stateList)
+		uvarint(uint64(len(v)))
+		for _, it := range v {
+			writeState(w, it)
+		}
+	case map[string]interface{}:
+		w.WriteByte(stateMap)
+		uvarint(uint64(len(v)))
+		for _, k := range sortedKeys(v) {
+			str(k)
+			writeState(w, v[k])
+		}
+	default:
+		panic(fmt.Sprintf("ta: unexpected state type %T", v))
+	}
+}
+
+func readState(r *bytes.Reader) (interface{}, error) {
+	str := func() (string, error) {
+		n, err := binary.ReadUvarint(r)
+		if err != nil || n > uint64(r.Len()) {
+			return "", io.ErrUnexpectedEOF
+		}
+		b := make([]byte, n)
+		_, err = io.ReadFull(r, b)
+		return string(b), err
+	}
+
+	tag, err := r.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+
+	switch tag {
+	case stateNil:
+		return nil, nil
+	case stateFalse, stateTrue:
+		return tag == stateTrue, nil
+	case stateIntTag:
+		return binary.ReadVarint(r)
+	case stateFloatTag:
+		var b [8]byte
+		if _, err := io.ReadFull(r, b[:]); err != nil {
+			return nil, err
+		}
+		return math.Float64frombits(binary.LittleEndian.Uint64(b[:])), nil
+	case stateString:
+		return str()
+	case stateList, stateMap:
+		n, err := binary.ReadUvarint(r)
+		if err != nil || n > uint64(r.Len()) {
+			return nil, io.ErrUnexpectedEOF
+		}
+		if tag == stateList {
+			out := make([]interface{}, n)
+			for i := range out {
+				if out[i], err = readState(r); err != nil {
+					return nil, err
+				}
+			}
+			return out, nil
+		}
+		out := make(map[string]interface{}, n)
+		for i := uint64(0); i < n; i++ {
+			k, err := str()
+			if err != nil {
+				return nil, err
+			}
+			if out[k], err = readState(r); err != nil {
+				return nil, err
+			}
+		}
+		return out, nil
+	default:
+		return nil, fmt.Errorf("unknown tag %d", tag)
+	}
+}
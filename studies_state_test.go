@@ -0,0 +1,304 @@
+package ta
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"testing"
+	"time"
+)
+
+func stateStudies() map[string]func() Study {
+	return map[string]func() Study{
+		"RSI":          func() Study { return RSI(14) },
+		"RSIExt":       func() Study { return RSIExt(KAMA(10)) },
+		"CMO":          func() Study { return CMO(14) },
+		"MACD":         func() Study { return MACD(12, 26, 9) },
+		"MACDExt":      func() Study { return MACDExt(12, 26, 9, TEMA) },
+		"VWAP":         func() Study { return VWAPBands(20, -20) },
+		"MinMax":       func() Study { return MinMax(10) },
+		"BBands":       func() Study { return BBands(20) },
+		"BBandsMA":     func() Study { return BollingerBands(20, 2, 2, DEMA) },
+		"OnCandles":    func() Study { return OnCandles(MACD(12, 26, 9), FieldHigh) },
+		"Patterns":     func() Study { return CandlePatterns(nil, AllCandlePatterns()...) },
+		"ADX":          func() Study { return ADX(14) },
+		"ADXR":         func() Study { return ADXR(14) },
+		"Aroon":        func() Study { return Aroon(14) },
+		"HTPhasor":     func() Study { return HTPhasor() },
+		"HTTrendMode":  func() Study { return HTTrendMode() },
+		"Ichimoku":     func() Study { return Ichimoku(0, 0, 0) },
+		"SMA":          func() Study { return SMA(10) },
+		"EMA":          func() Study { return EMA(10) },
+		"WMA":          func() Study { return WMA(10) },
+		"DEMA":         func() Study { return DEMA(10) },
+		"TEMA":         func() Study { return TEMA(10) },
+		"TRIMA":        func() Study { return TRIMA(10) },
+		"T3":           func() Study { return T3(5) },
+		"MAMA":         func() Study { return MAMAExt(10, 0.5, 0.05) },
+		"VWMA":         func() Study { return VWMA(10) },
+		"HMA":          func() Study { return HMA(10) },
+		"ZLEMA":        func() Study { return ZLEMA(10) },
+		"ALMA":         func() Study { return ALMA(10) },
+		"McGinley":     func() Study { return McGinley(10) },
+		"VIDYA":        func() Study { return VIDYA(10) },
+		"FRAMA":        func() Study { return FRAMA(10) },
+		"JMA":          func() Study { return JMA(10) },
+		"MOM":          func() Study { return MOM(10) },
+		"ROC":          func() Study { return ROC(10) },
+		"TRIX":         func() Study { return TRIX(10) },
+		"PPO":          func() Study { return PPO(12, 26, EMA) },
+		"BOP":          func() Study { return BOP() },
+		"MTF":          func() Study { return MultiTimeframe(func() Study { return RSI(5) }, 5, true) },
+		"StochSlow":    func() Study { return StochSlow(14, 3, 3, SMA) },
+		"StochRSI":     func() Study { return StochRSI(14, 5, 3, EMA) },
+		"WilliamsR":    func() Study { return WilliamsR(14) },
+		"CCI":          func() Study { return CCI(14) },
+		"UltOsc":       func() Study { return UltimateOscillator(7, 14, 28) },
+		"Pivots":       func() Study { return Pivots(PivotDeMark, nil) },
+		"ATR":          func() Study { return ATR(14) },
+		"NATR":         func() Study { return NATR(14) },
+		"Keltner":      func() Study { return KeltnerChannels(20, 10, 2, nil) },
+		"Donchian":     func() Study { return DonchianChannels(20) },
+		"Chandelier":   func() Study { return ChandelierExit(22, 3, nil) },
+		"SuperTrend":   func() Study { return SuperTrend(10, 3, nil) },
+		"LinearReg":    func() Study { return LinearReg(14) },
+		"Beta":         func() Study { return Beta(5) },
+		"Correl":       func() Study { return Correl(30) },
+		"Revisable":    func() Study { return Revisable(RSI(14)) },
+		"SAR":          func() Study { return SAR(0.02, 0.2) },
+		"Variance":     func() Study { return Variance(10) },
+		"Mean":         func() Study { return Mean(10) },
+		"OBV":          func() Study { return OBV() },
+		"ADOSC":        func() Study { return ADOSC(3, 10) },
+		"MFI":          func() Study { return MFI(14) },
+		"ForceIndex":   func() Study { return ForceIndex(13) },
+		"EMV":          func() Study { return EaseOfMovement(14, 0) },
+		"ZigZag":       func() Study { return ZigZag(5) },
+		"ZigZagATR":    func() Study { return ZigZagATR(14, 3) },
+		"Locked":       func() Study { return LockedStudy(EMA(10)) },
+		"LockedMulti":  func() Study { return LockedMulti(MACD(12, 26, 9)) },
+		"LinearRegTSF": func() Study { return TSF(14) },
+		"KAMA":         func() Study { return KAMA(10) },
+		"TRange":       func() Study { return TRange() },
+		"StochFast":    func() Study { return StochFast(14, 3, nil) },
+		"DX":           func() Study { return DX(14) },
+		"HTSine":       func() Study { return HTSine() },
+		"ROCR":         func() Study { return ROCR(10) },
+		"Readiness":    func() Study { return Readiness(MACD(12, 26, 9)) },
+		"Chain":        func() Study { return Chain(ATR(14), EMA(5)) },
+		"Fan":          func() Study { return Fan(RSI(14), Output(MACD(12, 26, 9), 1)) },
+		"Combine":      func() Study { return Sub(Price(), Mul(Const(2), SMA(20))) },
+		"Expr":         func() Study { return MustParseExpr("crossover(rsi(hl2, 14), 50) + atr(5)[1]") },
+	}
+}
+
+// updateState feeds c to s the way each study expects it
+func updateState(name string, s Study, c *Candle) []Decimal {
+	switch name {
+	case "VWAP":
+		return s.(MultiVarStudy).UpdateAll(Decimal(c.Volume), c.Close)
+	case "Beta", "Correl":
+		return []Decimal{s.Update(c.High, c.Low)}
+	}
+	if cs, ok := s.(CandleStudy); ok {
+		return cs.UpdateCandle(c)
+	}
+	if ms, ok := s.ToMulti(); ok {
+		return ms.UpdateAll(c.Close)
+	}
+	return []Decimal{s.Update(c.Close)}
+}
+
+func sameBits(a, b []Decimal) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if math.Float64bits(float64(a[i])) != math.Float64bits(float64(b[i])) {
+			return false
+		}
+	}
+	return true
+}
+
+func TestStudyState(t *testing.T) {
+	candles := testCandles()
+	half := len(candles) / 2
+	for name, fn := range stateStudies() {
+		for _, format := range []string{"binary", "json"} {
+			orig := fn()
+			for _, c := range candles[:half] {
+				updateState(name, orig, c)
+			}
+
+			marshal := MarshalStudy
+			if format == "json" {
+				marshal = MarshalStudyJSON
+			}
+			data, err := marshal(orig)
+			if err != nil {
+				t.Fatalf("%s (%s): %v", name, format, err)
+			}
+
+			restored := fn()
+			if err := UnmarshalStudy(restored, data); err != nil {
+				t.Fatalf("%s (%s): %v", name, format, err)
+			}
+
+			for i, c := range candles[half:] {
+				if exp, got := updateState(name, orig, c), updateState(name, restored, c); !sameBits(exp, got) {
+					t.Fatalf("%s (%s) [%d]: expected %v, got %v", name, format, i, exp, got)
+				}
+			}
+		}
+	}
+}
+
+func TestStudyStateErrors(t *testing.T) {
+	s := RSI(14)
+	for _, c := range testCandles()[:20] {
+		s.Update(c.Close)
+	}
+	data, err := MarshalStudyJSON(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := UnmarshalStudy(EMA(14), data); !errors.Is(err, ErrStateType) {
+		t.Fatalf("expected ErrStateType, got %v", err)
+	}
+
+	newer := strings.Replace(string(data), `"version":1`, `"version":2`, 1)
+	if err := UnmarshalStudy(RSI(14), []byte(newer)); !errors.Is(err, ErrStateVersion) {
+		t.Fatalf("expected ErrStateVersion, got %v", err)
+	}
+
+	bin, _ := MarshalStudy(s)
+	if err := UnmarshalStudy(RSI(14), bin[:len(bin)-3]); !errors.Is(err, ErrStateFormat) {
+		t.Fatalf("expected ErrStateFormat, got %v", err)
+	}
+
+	// the parameters must match, the target isn't changed
+	for _, c := range []struct {
+		s      Study
+		period int
+		msg    string
+	}{
+		{RSI(20), 20, "study.state.per: the study has 0.05, the snapshot has 0.0714"},
+		{CMO(14), 14, "study.state.cmo: the study has true, the snapshot has false"},
+	} {
+		if err := UnmarshalStudy(c.s, data); !errors.Is(err, ErrStateType) || !strings.Contains(err.Error(), c.msg) {
+			t.Fatalf("expected %q, got %v", c.msg, err)
+		}
+		if r := c.s.(*rsi); r.period != c.period || r.idx != 0 {
+			t.Fatalf("the target was changed: %+v", r)
+		}
+	}
+
+	// missing and unknown state fields are rejected
+	st := strings.LastIndex(string(data), `"study":`)
+	for _, bad := range []string{
+		string(data[:st]) + strings.Replace(string(data[st:]), `"cmo":false,`, ``, 1),
+		string(data[:st]) + strings.Replace(string(data[st:]), `"cmo":false,`, `"cmo":false,"cmo2":false,`, 1),
+	} {
+		r := RSI(14)
+		if err := UnmarshalStudy(r, []byte(bad)); !errors.Is(err, ErrStateType) {
+			t.Fatalf("expected ErrStateType, got %v", err)
+		}
+		if r.(*rsi).idx != 0 {
+			t.Fatalf("the target was changed: %+v", r)
+		}
+	}
+
+	// nested studies must match
+	data, _ = MarshalStudy(RSIExt(KAMA(10)))
+	if err := UnmarshalStudy(RSIExt(WMA(10)), data); !errors.Is(err, ErrStateType) {
+		t.Fatalf("expected ErrStateType, got %v", err)
+	}
+
+	// timestamps are part of the state
+	p := Pivots(PivotClassic, nil)
+	t0 := time.Date(2021, 3, 1, 10, 0, 0, 0, time.UTC)
+	p.UpdateCandleAt(t0, &Candle{Open: 1, High: 2, Low: 0.5, Close: 1.5})
+	data, _ = MarshalStudy(p)
+	rp := Pivots(PivotClassic, nil)
+	if err := UnmarshalStudy(rp, data); err != nil {
+		t.Fatal(err)
+	}
+	c := &Candle{Open: 1.5, High: 3, Low: 1, Close: 2}
+	if exp, got := p.UpdateCandleAt(t0.Add(time.Hour), c), rp.UpdateCandleAt(t0.Add(time.Hour), c); !sameBits(exp, got) {
+		t.Fatalf("expected %v, got %v", exp, got)
+	}
+}
+
+func TestStudyStatePendingRevision(t *testing.T) {
+	candles := testCandles()
+	for _, marshal := range []func(Study) ([]byte, error){MarshalStudy, MarshalStudyJSON} {
+		orig := Revisable(RSI(14))
+		for _, c := range candles[:30] {
+			orig.Update(c.Close)
+		}
+		orig.Revise(candles[30].High)
+
+		data, err := marshal(orig)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rs := Revisable(RSI(14))
+		if err := UnmarshalStudy(rs, data); err != nil {
+			t.Fatal(err)
+		}
+
+		// the revision is still pending, so the next one replaces it
+		if exp, got := orig.Revise(candles[30].Low), rs.Revise(candles[30].Low); !sameBits(exp, got) {
+			t.Fatalf("expected %v, got %v", exp, got)
+		}
+		orig.Commit()
+		rs.Commit()
+		for i, c := range candles[31:] {
+			if exp, got := orig.Update(c.Close), rs.Update(c.Close); !sameBits([]Decimal{exp}, []Decimal{got}) {
+				t.Fatalf("[%d] expected %v, got %v", i, exp, got)
+			}
+		}
+	}
+
+	orig := Revisable(SMA(3))
+	orig.Update(1)
+	orig.Update(2)
+	orig.Revise(10)
+	data, _ := MarshalStudy(orig)
+	rs := Revisable(SMA(3))
+	if err := UnmarshalStudy(rs, data); err != nil {
+		t.Fatal(err)
+	}
+	if exp, got := orig.Revise(3)[0], rs.Revise(3)[0]; exp != 2 || got != 2 {
+		t.Fatalf("expected 2, got %v %v", exp, got)
+	}
+}
+
+func TestStudyStateLocked(t *testing.T) {
+	s := LockedStudy(EMA(10))
+	stop, done := make(chan struct{}), make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; ; i++ {
+			select {
+			case <-stop:
+				return
+			default:
+				s.Update(testClose.Get(i % testClose.Len()))
+			}
+		}
+	}()
+	for i := 0; i < 500; i++ {
+		data, err := MarshalStudy(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := UnmarshalStudy(s, data); err != nil {
+			t.Fatal(err)
+		}
+	}
+	close(stop)
+	<-done
+}
@@ -1,8 +1,14 @@
 package ta
 
 import (
+	"fmt"
+	"math"
+	"strings"
 	"sync"
 	"testing"
+	"time"
+
+	"go.oneofone.dev/ta/decimal"
 )
 
 func wrapMA(ma MovingAverageFunc) func(p int) Study {
@@ -16,6 +22,18 @@ func TestWMA(t *testing.T)  { testMA(t, "WMA", WMA, -1) }
 func TestDEMA(t *testing.T) { testMA(t, "DEMA", DEMA, 36) }
 func TestTEMA(t *testing.T) { testMA(t, "TEMA", TEMA, 32) }
 
+func TestTRIMA(t *testing.T) { testMA(t, "TRIMA", TRIMA, -1) }
+func TestKAMA(t *testing.T)  { testMA(t, "KAMA", KAMA, -1) }
+func TestT3(t *testing.T)    { testMA(t, "T3", T3, 36) }
+
+func TestMAMA(t *testing.T) {
+	t.Parallel()
+	tests := &[...]*[2]Decimal{{0.5, 0.05}, {0.4, 0.1}, {0.9, 0.02}}
+	for _, ts := range tests {
+		testMAMA(t, ts[0], ts[1])
+	}
+}
+
 func TestRSI(t *testing.T) { testStudy(t, "RSI", RSI, -1) }
 func TestVar(t *testing.T) {
 	vari := func(period int) Study {
@@ -36,6 +54,7 @@ func TestMACD(t *testing.T) {
 		testMACD(t, ts[0], ts[1], ts[2], WMA, "WMA")
 		testMACD(t, ts[0], ts[1], ts[2], DEMA, "DEMA")
 		testMACD(t, ts[0], ts[1], ts[2], TEMA, "TEMA")
+		testMACD(t, ts[0], ts[1], ts[2], TRIMA, "TRIMA")
 	}
 }
 
@@ -57,6 +76,10 @@ func TestBBands(t *testing.T) {
 			testBBands(t, &wg, p, ts[0], ts[1], WMA, "WMA")
 			testBBands(t, &wg, p, ts[0], ts[1], DEMA, "DEMA")
 			testBBands(t, &wg, p, ts[0], ts[1], TEMA, "TEMA")
+			testBBands(t, &wg, p, ts[0], ts[1], TRIMA, "TRIMA")
+			testBBands(t, &wg, p, ts[0], ts[1], KAMA, "KAMA")
+			testBBands(t, &wg, p, ts[0], ts[1], MAMA, "MAMA")
+			testBBands(t, &wg, p, ts[0], ts[1], T3, "T3")
 		}
 	}
 	wg.Wait()
@@ -95,3 +118,1091 @@ func TestVWAP(t *testing.T) {
 	t.Log(bb[1])
 	t.Log(bb[2])
 }
+
+func TestOnCandles(t *testing.T) {
+	candles := testCandles()
+
+	exp := ApplyStudy(SMA(10), testClose)
+	if res := ApplyCandleSlice(OnCandles(SMA(10), FieldClose), candles)[0]; !exp.Equal(res) {
+		t.Fatalf("expected %v, got %v", exp, res)
+	}
+
+	exp = ApplyStudy(SMA(10), HLC3(testHigh, testLow, testClose))
+	cs := &Candles{High: testHigh, Low: testLow, Close: testClose}
+	if res := ApplyCandles(OnCandles(SMA(10), FieldHLC3), cs)[0]; !exp.Equal(res) {
+		t.Fatalf("expected %v, got %v", exp, res)
+	}
+
+	bb := ApplyMultiVarStudy(BBands(10), testClose)
+	res := ApplyCandles(OnCandles(BBands(10), nil), CandlesFromSlice(candles))
+	if len(res) != len(bb) {
+		t.Fatalf("expected %d values, got %d", len(bb), len(res))
+	}
+	for i := range bb {
+		if !bb[i].Equal(res[i]) {
+			t.Fatalf("[%d] expected %v, got %v", i, bb[i], res[i])
+		}
+	}
+}
+
+const testHLC = "testHigh, testLow, testClose"
+
+func TestTRange(t *testing.T) {
+	// talib doesn't return the first value
+	res := ApplyCandles(TRange(), testColumns)[0].Slice(1, 0)
+	compare(t, res, "result = talib.TRANGE(%s)", testHLC)
+}
+
+func TestATR(t *testing.T)  { testCandleStudy(t, "ATR", testHLC, ATR, -1) }
+func TestNATR(t *testing.T) { testCandleStudy(t, "NATR", testHLC, NATR, -1) }
+
+func TestPlusDI(t *testing.T)  { testCandleStudy(t, "PLUS_DI", testHLC, PlusDI, -1) }
+func TestMinusDI(t *testing.T) { testCandleStudy(t, "MINUS_DI", testHLC, MinusDI, -1) }
+func TestDX(t *testing.T)      { testCandleStudy(t, "DX", testHLC, DX, -1) }
+func TestADX(t *testing.T)     { testCandleStudy(t, "ADX", testHLC, ADX, 120) }
+func TestADXR(t *testing.T)    { testCandleStudy(t, "ADXR", testHLC, ADXR, 71) }
+
+func TestAroon(t *testing.T) {
+	testCandleStudy(t, "AROONOSC", "testHigh, testLow", Aroon, -1)
+	for _, p := range &[...]int{2, 5, 14, 25} {
+		ar := ApplyCandles(Aroon(p), testColumns)
+		compare(t, ar[0], "_, result = talib.AROON(testHigh, testLow, %d)", p)
+		compare(t, ar[1], "result, _ = talib.AROON(testHigh, testLow, %d)", p)
+	}
+}
+
+func TestStoch(t *testing.T) {
+	for _, c := range &[...][3]int{{5, 3, 3}, {14, 3, 3}, {14, 1, 3}, {21, 5, 7}} {
+		st := ApplyCandles(StochSlow(c[0], c[1], c[2], nil), testColumns)
+		pyfn := fmt.Sprintf("talib.STOCH(%s, %d, %d, 0, %d, 0)", testHLC, c[0], c[1], c[2])
+		compare(t, st[0], "result, _ = %s", pyfn)
+		compare(t, st[1], "_, result = %s", pyfn)
+
+		st = ApplyCandles(StochFull(c[0], c[1], c[2], EMA, WMA), testColumns)
+		pyfn = fmt.Sprintf("talib.STOCH(%s, %d, %d, 1, %d, 2)", testHLC, c[0], c[1], c[2])
+		compare(t, st[0], "result, _ = %s", pyfn)
+		compare(t, st[1], "_, result = %s", pyfn)
+	}
+}
+
+func TestStochFast(t *testing.T) {
+	for _, c := range &[...][2]int{{5, 3}, {14, 3}, {21, 7}} {
+		st := ApplyCandles(StochFast(c[0], c[1], nil), testColumns)
+		pyfn := fmt.Sprintf("talib.STOCHF(%s, %d, %d, 0)", testHLC, c[0], c[1])
+		compare(t, st[0], "result, _ = %s", pyfn)
+		compare(t, st[1], "_, result = %s", pyfn)
+	}
+}
+
+func TestStochRSI(t *testing.T) {
+	for _, c := range &[...][3]int{{14, 5, 3}, {14, 14, 3}, {10, 5, 5}} {
+		st := ApplyMultiVarStudy(StochRSI(c[0], c[1], c[2], nil), testClose)
+		pyfn := fmt.Sprintf("talib.STOCHRSI(testClose, %d, %d, %d, 0)", c[0], c[1], c[2])
+		compare(t, st[0], "result, _ = %s", pyfn)
+		compare(t, st[1], "_, result = %s", pyfn)
+	}
+}
+
+func TestWilliamsR(t *testing.T) { testCandleStudy(t, "WILLR", testHLC, WilliamsR, -1) }
+func TestCCI(t *testing.T)       { testCandleStudy(t, "CCI", testHLC, CCI, -1) }
+func TestCMO(t *testing.T)       { testStudy(t, "CMO", CMO, -1) }
+
+func TestUltimateOscillator(t *testing.T) {
+	for _, c := range &[...][3]int{{7, 14, 28}, {28, 7, 14}, {2, 3, 5}, {10, 20, 40}} {
+		res := ApplyCandles(UltimateOscillator(c[0], c[1], c[2]), testColumns)[0]
+		compare(t, res, "result = talib.ULTOSC(%s, %d, %d, %d)", testHLC, c[0], c[1], c[2])
+	}
+}
+
+const testHLCV = "testHigh, testLow, testClose, testVolume"
+
+func TestOBV(t *testing.T) {
+	res := ApplyCandles(OBV(), testColumns)[0]
+	compare(t, res, "result = talib.OBV(testClose, testVolume)")
+}
+
+func TestAD(t *testing.T) {
+	res := ApplyCandles(AD(), testColumns)[0]
+	compare(t, res, "result = talib.AD(%s)", testHLCV)
+}
+
+func TestADOSC(t *testing.T) {
+	for _, c := range &[...][2]int{{3, 10}, {5, 20}, {10, 3}} {
+		res := ApplyCandles(ADOSC(c[0], c[1]), testColumns)[0]
+		compare(t, res, "result = talib.ADOSC(%s, %d, %d)", testHLCV, c[0], c[1])
+	}
+}
+
+func TestMFI(t *testing.T) { testCandleStudy(t, "MFI", testHLCV, MFI, -1) }
+
+func TestVWMA(t *testing.T) {
+	// with a constant volume it's just an SMA
+	ones := testClose.Map(func(Decimal) Decimal { return 1 }, false)
+	res := ApplyStudy(VWMA(10), ones, testClose)
+	exp := ApplyStudy(SMA(10), testClose)
+	if !res.Equal(exp) {
+		t.Fatalf("expected %v, got %v", exp.Slice(-5, 0), res.Slice(-5, 0))
+	}
+
+	res = ApplyStudy(VWMA(3), testVolume, testClose)
+	var pv, vol Decimal
+	for i := testClose.Len() - 3; i < testClose.Len(); i++ {
+		pv += testClose.Get(i) * testVolume.Get(i)
+		vol += testVolume.Get(i)
+	}
+	if !decimal.EqualApprox(res.Last().Float(), (pv / vol).Float(), 1e-9) {
+		t.Fatalf("expected %v, got %v", pv/vol, res.Last())
+	}
+}
+
+func TestForceIndex(t *testing.T) {
+	raw := NewSize(testClose.Len()-1, true)
+	for i := 1; i < testClose.Len(); i++ {
+		raw.Append((testClose.Get(i) - testClose.Get(i-1)) * testVolume.Get(i))
+	}
+	res := ApplyCandles(ForceIndex(13), testColumns)[0]
+	exp := ApplyStudy(EMA(13), raw)
+	if !res.Equal(exp) {
+		t.Fatalf("expected %v, got %v", exp.Slice(-5, 0), res.Slice(-5, 0))
+	}
+
+	res = ApplyCandles(ForceIndex(1), testColumns)[0].Slice(1, 0)
+	if !res.Equal(raw) {
+		t.Fatalf("expected %v, got %v", raw.Slice(-5, 0), res.Slice(-5, 0))
+	}
+}
+
+func TestEaseOfMovement(t *testing.T) {
+	raw := NewSize(testClose.Len()-1, true)
+	for i := 1; i < testClose.Len(); i++ {
+		h, l, ph, pl := testHigh.Get(i), testLow.Get(i), testHigh.Get(i-1), testLow.Get(i-1)
+		raw.Append(10000 * ((h+l)/2 - (ph+pl)/2) * (h - l) / testVolume.Get(i))
+	}
+	res := ApplyCandles(EaseOfMovement(14, 0), testColumns)[0]
+	exp := ApplyStudy(SMA(14), raw)
+	for i := 0; i < res.Len(); i++ {
+		if !decimal.EqualApprox(res.Get(i).Float(), exp.Get(i).Float(), 1e-6) {
+			t.Fatalf("[%d] expected %v, got %v", i, exp.Get(i), res.Get(i))
+		}
+	}
+}
+
+func TestMOM(t *testing.T)     { testStudy(t, "MOM", MOM, -1) }
+func TestROC(t *testing.T)     { testStudy(t, "ROC", ROC, -1) }
+func TestROCP(t *testing.T)    { testStudy(t, "ROCP", ROCP, -1) }
+func TestROCR(t *testing.T)    { testStudy(t, "ROCR", ROCR, -1) }
+func TestROCR100(t *testing.T) { testStudy(t, "ROCR100", ROCR100, -1) }
+func TestTRIX(t *testing.T)    { testStudy(t, "TRIX", TRIX, 71) }
+
+// TRIX is ROC(1) of EMA(EMA(EMA)), each EMA starts with the first valid value of the previous one
+func TestTRIXChain(t *testing.T) {
+	ema := func(in []Decimal, p int) (out []Decimal) {
+		e := EMA(p)
+		for i, v := range in {
+			if v = e.Update(v); i >= p-1 {
+				out = append(out, v)
+			}
+		}
+		return out
+	}
+	for _, p := range []int{2, 5, 15} {
+		e3 := ema(ema(ema(testClose.v, p), p), p)
+		trix := TRIX(p)
+		for i := 0; i < testClose.Len(); i++ {
+			got := trix.Update(testClose.Get(i))
+			j := i - 3*(p-1)
+			if j < 1 {
+				if got != 0 {
+					t.Fatalf("%d [%d]: expected 0, got %v", p, i, got)
+				}
+				continue
+			}
+			if exp := (e3[j]/e3[j-1] - 1) * 100; !decimal.EqualApprox(exp.Float(), got.Float(), 1e-9) {
+				t.Fatalf("%d [%d]: expected %v, got %v", p, i, exp, got)
+			}
+		}
+	}
+}
+
+func TestAPO(t *testing.T) {
+	for _, c := range &[...][2]int{{12, 26}, {5, 10}, {26, 12}} {
+		for typ, ma := range map[string]MovingAverageFunc{"SMA": SMA, "EMA": EMA, "WMA": WMA} {
+			res := ApplyStudy(APO(c[0], c[1], ma), testClose)
+			compare(t, res, "result = talib.APO(testClose, %d, %d, talib.MA_Type.%s)", c[0], c[1], typ)
+			res = ApplyStudy(PPO(c[0], c[1], ma), testClose)
+			compare(t, res, "result = talib.PPO(testClose, %d, %d, talib.MA_Type.%s)", c[0], c[1], typ)
+		}
+	}
+}
+
+func TestBOP(t *testing.T) {
+	res := ApplyCandles(BOP(), testColumns)[0]
+	compare(t, res, "result = talib.BOP(testOpen, %s)", testHLC)
+}
+
+func testLinearReg(t *testing.T, name string, fn func(period int) MultiVarStudy) {
+	testStudy(t, name, func(period int) Study { return fn(period) }, -1)
+}
+
+func TestLinearReg(t *testing.T) {
+	testLinearReg(t, "LINEARREG", LinearReg)
+}
+
+func TestLinearRegSlope(t *testing.T) {
+	testLinearReg(t, "LINEARREG_SLOPE", LinearRegSlope)
+}
+
+func TestLinearRegIntercept(t *testing.T) {
+	testLinearReg(t, "LINEARREG_INTERCEPT", LinearRegIntercept)
+}
+
+func TestLinearRegAngle(t *testing.T) {
+	testLinearReg(t, "LINEARREG_ANGLE", LinearRegAngle)
+}
+
+func TestTSF(t *testing.T) {
+	testLinearReg(t, "TSF", TSF)
+}
+
+func TestBeta(t *testing.T) {
+	for _, p := range &[...]int{1, 5, 10, 30} {
+		res := ApplyStudy(Beta(p), testClose, testOpen)
+		compare(t, res, "result = talib.BETA(testClose, testOpen, %d)", p)
+	}
+}
+
+func TestCorrel(t *testing.T) {
+	for _, p := range &[...]int{2, 5, 10, 30} {
+		res := ApplyStudy(Correl(p), testHigh, testLow)
+		compare(t, res, "result = talib.CORREL(testHigh, testLow, %d)", p)
+	}
+}
+
+func TestHTDCPeriod(t *testing.T) {
+	res := ApplyStudy(HTDCPeriod(), testClose).Slice(32, 0)
+	compare(t, res, "result = talib.HT_DCPERIOD(testClose)")
+}
+
+func TestHTDCPhase(t *testing.T) {
+	res := ApplyStudy(HTDCPhase(), testClose).Slice(63, 0)
+	compare(t, res, "result = talib.HT_DCPHASE(testClose)")
+}
+
+func TestHTPhasor(t *testing.T) {
+	res := ApplyMultiVarStudy(HTPhasor(), testClose)
+	compare(t, res[0].Slice(32, 0), "result, _ = talib.HT_PHASOR(testClose)")
+	compare(t, res[1].Slice(32, 0), "_, result = talib.HT_PHASOR(testClose)")
+}
+
+func TestHTSine(t *testing.T) {
+	res := ApplyMultiVarStudy(HTSine(), testClose)
+	compare(t, res[0].Slice(63, 0), "result, _ = talib.HT_SINE(testClose)")
+	compare(t, res[1].Slice(63, 0), "_, result = talib.HT_SINE(testClose)")
+}
+
+func TestHTTrendline(t *testing.T) {
+	res := ApplyStudy(HTTrendline(), testClose).Slice(63, 0)
+	compare(t, res, "result = talib.HT_TRENDLINE(testClose)")
+}
+
+func TestHTTrendMode(t *testing.T) {
+	res := ApplyStudy(HTTrendMode(), testClose).Slice(63, 0)
+	compare(t, res, "result = talib.HT_TRENDMODE(testClose)")
+}
+
+func TestCandlePatterns(t *testing.T) {
+	for _, p := range AllCandlePatterns() {
+		p := p
+		t.Run(p.String(), func(t *testing.T) {
+			res := ApplyCandles(CandlePatternStudy(p, nil), testColumns)[0]
+			compare(t, res, "result = talib.%s(testOpen, %s)", p, testHLC)
+		})
+	}
+	for _, p := range &[...]Decimal{0.1, 0.7} {
+		cs := DefaultCandleSettings()
+		cs.Penetration = p
+		res := ApplyCandles(CandlePatternStudy(CDLMorningStar, cs), testColumns)[0]
+		compare(t, res, "result = talib.CDLMORNINGSTAR(testOpen, %s, %v)", testHLC, p)
+	}
+}
+
+func TestCandlePatternsUpdate(t *testing.T) {
+	var (
+		ps      = AllCandlePatterns()
+		candles = testCandles()
+		all     = ApplyCandleSlice(CandlePatterns(nil, ps...), candles)
+		st      = CandlePatterns(nil, ps...)
+		upd     = CandlePatterns(nil, ps...)
+	)
+	for i, p := range ps {
+		if res := ApplyCandles(CandlePatternStudy(p, nil), testColumns)[0]; !res.Equal(all[i]) {
+			t.Fatalf("%s: expected %v, got %v", p, res, all[i])
+		}
+		if pp, ok := ParseCandlePattern(strings.ToLower(p.String()[3:])); !ok || pp != p {
+			t.Fatalf("ParseCandlePattern(%s): %v %v", p, pp, ok)
+		}
+	}
+
+	for i, c := range candles {
+		var first Decimal
+		for j, v := range st.UpdateCandle(c) {
+			if v != all[j].Get(i) {
+				t.Fatalf("[%d] %s: expected %v, got %v", i, ps[j], all[j].Get(i), v)
+			}
+			if first == 0 {
+				first = v
+			}
+		}
+		if v := upd.Update(c.Values()...); v != first {
+			t.Fatalf("[%d] Update: expected %v, got %v", i, first, v)
+		}
+	}
+}
+
+func TestCandlePatternsDoji(t *testing.T) {
+	doji := CandlePatternStudy(CDLDoji, nil)
+	for i := 0; i < CDLDoji.Lookback(nil); i++ {
+		doji.Update(10, 12, 8, 11)
+	}
+	if v := doji.Update(10, 12, 8, 10.05); v != 100 {
+		t.Fatalf("expected a doji, got %v", v)
+	}
+	if v := doji.Update(10, 12, 8, 11); v != 0 {
+		t.Fatalf("expected no doji, got %v", v)
+	}
+}
+
+func TestSAR(t *testing.T) {
+	for _, c := range &[...][2]Decimal{{0.02, 0.2}, {0.01, 0.1}, {0.05, 0.5}} {
+		res := ApplyCandles(SAR(c[0], c[1]), testColumns)[0].Slice(1, 0)
+		compare(t, res, "result = talib.SAR(testHigh, testLow, %v, %v)", c[0], c[1])
+	}
+}
+
+func TestSARExt(t *testing.T) {
+	for _, c := range &[...][8]Decimal{
+		{0, 0, 0.02, 0.02, 0.2, 0.02, 0.02, 0.2},
+		{200, 0.01, 0.01, 0.02, 0.2, 0.03, 0.03, 0.3},
+		{-205, 0, 0.02, 0.01, 0.1, 0.05, 0.02, 0.2},
+	} {
+		res := ApplyCandles(SARExt(c[0], c[1], c[2], c[3], c[4], c[5], c[6], c[7]), testColumns)[0].Slice(1, 0)
+		compare(t, res, "result = numpy.abs(talib.SAREXT(testHigh, testLow, %v, %v, %v, %v, %v, %v, %v, %v))",
+			c[0], c[1], c[2], c[3], c[4], c[5], c[6], c[7])
+	}
+}
+
+func TestSARDirection(t *testing.T) {
+	var (
+		sar = SAR(0.02, 0.2)
+		ext = SARExt(0, 0, 0.02, 0.02, 0.2, 0.02, 0.02, 0.2)
+	)
+	for i, c := range testCandles() {
+		v := sar.UpdateCandle(c)
+		if ev := ext.UpdateCandle(c); ev[0] != v[0] || ev[1] != v[1] {
+			t.Fatalf("[%d] expected %v, got %v", i, v, ev)
+		}
+		switch {
+		case i == 0:
+			if v[1] != 0 {
+				t.Fatalf("[%d] expected no direction, got %v", i, v[1])
+			}
+		case v[1] > 0 && v[0] > c.Low, v[1] < 0 && v[0] < c.High:
+			t.Fatalf("[%d] sar %v (direction %v) is on the wrong side of [%v, %v]", i, v[0], v[1], c.Low, c.High)
+		}
+	}
+}
+
+func TestIchimoku(t *testing.T) {
+	res := ApplyCandles(Ichimoku(0, 0, 0), testColumns)
+	compare(t, res[IchimokuTenkan].Slice(8, 0), "result = (talib.MAX(testHigh, 9) + talib.MIN(testLow, 9)) / 2")
+	compare(t, res[IchimokuKijun].Slice(25, 0), "result = (talib.MAX(testHigh, 26) + talib.MIN(testLow, 26)) / 2")
+	// senkou B starts 26 bars ahead and has 26 extra bars
+	sb := res[IchimokuSenkouB]
+	compare(t, sb.Slice(26+51, sb.Len()-26), "result = (talib.MAX(testHigh, 52) + talib.MIN(testLow, 52)) / 2")
+}
+
+func TestIchimokuDisplacement(t *testing.T) {
+	var (
+		candles = testCandles()
+		ln      = len(candles)
+		batch   = ApplyMultiVarStudy(Ichimoku(9, 26, 52), testHigh, testLow, testClose)
+		live    = Ichimoku(9, 26, 52)
+	)
+	if d := live.Displacement(); d[IchimokuSenkouA] != 26 || d[IchimokuSenkouB] != 26 || d[IchimokuChikou] != -26 {
+		t.Fatalf("unexpected displacement: %v", d)
+	}
+	if n := batch[IchimokuSenkouA].Len(); n != ln+26 {
+		t.Fatalf("expected %d senkou values, got %d", ln+26, n)
+	}
+	if n := batch[IchimokuChikou].Len(); n != ln {
+		t.Fatalf("expected %d chikou values, got %d", ln, n)
+	}
+
+	for i, c := range candles {
+		vs := live.UpdateCandle(c)
+		if v := batch[IchimokuTenkan].Get(i); v != vs[IchimokuTenkan] {
+			t.Fatalf("[%d] tenkan: expected %v, got %v", i, v, vs[IchimokuTenkan])
+		}
+		if v := batch[IchimokuSenkouA].Get(i + 26); v != vs[IchimokuSenkouA] {
+			t.Fatalf("[%d] senkou A: expected %v, got %v", i, v, vs[IchimokuSenkouA])
+		}
+		if v := batch[IchimokuSenkouB].Get(i + 26); v != vs[IchimokuSenkouB] {
+			t.Fatalf("[%d] senkou B: expected %v, got %v", i, v, vs[IchimokuSenkouB])
+		}
+
+		a, b := live.Cloud()
+		switch {
+		case i < 26:
+			if !batch[IchimokuSenkouA].Get(i).IsNaN() || a != 0 || b != 0 {
+				t.Fatalf("[%d] expected no cloud, got %v %v", i, a, b)
+			}
+		case a != batch[IchimokuSenkouA].Get(i) || b != batch[IchimokuSenkouB].Get(i):
+			t.Fatalf("[%d] cloud: expected %v %v, got %v %v", i, batch[IchimokuSenkouA].Get(i), batch[IchimokuSenkouB].Get(i), a, b)
+		}
+
+		chikou := batch[IchimokuChikou].Get(i)
+		if i+26 < ln && chikou != candles[i+26].Close || i+26 >= ln && !chikou.IsNaN() {
+			t.Fatalf("[%d] chikou: got %v", i, chikou)
+		}
+	}
+}
+
+func TestAdaptiveMA(t *testing.T) {
+	// the reference functions are straightforward batch versions of each formula,
+	// NaN values are the warm up of the batch version
+	refs := []struct {
+		name string
+		fn   MovingAverageFunc
+		ref  func(in []float64, p int) []float64
+	}{
+		{"HMA", HMA, refHMA},
+		{"ZLEMA", ZLEMA, refZLEMA},
+		{"ALMA", ALMA, refALMA},
+		{"McGinley", McGinley, refMcGinley},
+		{"VIDYA", VIDYA, refVIDYA},
+		{"FRAMA", FRAMA, refFRAMA},
+		{"JMA", JMA, refJMA},
+	}
+
+	in := testClose.Floats()
+	for _, c := range refs {
+		for _, p := range []int{4, 9, 10, 21} {
+			var (
+				ma  = c.fn(p)
+				exp = c.ref(in, p)
+				n   int
+			)
+			for i, v := range in {
+				got := ma.Update(Decimal(v))
+				if math.IsNaN(exp[i]) {
+					continue
+				}
+				if n++; !decimal.EqualApprox(got.Float(), exp[i], 1e-9) {
+					t.Fatalf("%s(%d) [%d]: expected %v, got %v", c.name, p, i, exp[i], got)
+				}
+			}
+			if n < len(in)-2*p {
+				t.Fatalf("%s(%d): only %d values were compared", c.name, p, n)
+			}
+		}
+	}
+
+	// a zero price doesn't restart the McGinley Dynamic
+	if v := McGinley(5).Update(0, 10); v != 0 {
+		t.Fatalf("expected 0, got %v", v)
+	}
+
+	// or break it in the middle of the series
+	mg := McGinley(5)
+	for i, v := range []Decimal{10, 0, 11, 12, 9} {
+		got := mg.Update(v)
+		if got.IsNaN() || got.IsInf() || i == 1 && got != 10 {
+			t.Fatalf("[%d] unexpected %v", i, got)
+		}
+	}
+}
+
+func nanSlice(n int) []float64 {
+	out := make([]float64, n)
+	for i := range out {
+		out[i] = math.NaN()
+	}
+	return out
+}
+
+func refWMA(in []float64, p int) []float64 {
+	out := nanSlice(len(in))
+	for i := p - 1; i < len(in); i++ {
+		if math.IsNaN(in[i-p+1]) {
+			continue
+		}
+		var sum float64
+		for j := 0; j < p; j++ {
+			sum += float64(p-j) * in[i-j]
+		}
+		out[i] = sum / float64(p*(p+1)/2)
+	}
+	return out
+}
+
+func refHMA(in []float64, p int) []float64 {
+	half, full := in, refWMA(in, p)
+	if p/2 > 1 {
+		half = refWMA(in, p/2)
+	}
+	diff := make([]float64, len(in))
+	for i := range diff {
+		diff[i] = 2*half[i] - full[i]
+	}
+	if s := int(math.Sqrt(float64(p))); s > 1 {
+		return refWMA(diff, s)
+	}
+	return diff
+}
+
+func refZLEMA(in []float64, p int) []float64 {
+	lag := (p - 1) / 2
+	x := make([]float64, len(in))
+	for i, v := range in {
+		x[i] = v
+		if i >= lag {
+			x[i] = 2*v - in[i-lag]
+		}
+	}
+	out, k := nanSlice(len(in)), 2/float64(p+1)
+	var ema float64
+	for i, v := range x {
+		switch {
+		case i < p-1:
+			ema += v
+			continue
+		case i == p-1:
+			ema = (ema + v) / float64(p)
+		default:
+			ema += (v - ema) * k
+		}
+		out[i] = ema
+	}
+	return out
+}
+
+func refALMA(in []float64, p int) []float64 {
+	var (
+		m   = 0.85 * float64(p-1)
+		s   = float64(p) / 6
+		w   = make([]float64, p)
+		out = make([]float64, len(in))
+	)
+	for j := range w {
+		w[j] = math.Exp(-(float64(j) - m) * (float64(j) - m) / (2 * s * s))
+	}
+	for i := range in {
+		n := i + 1
+		if n > p {
+			n = p
+		}
+		var sum, wsum float64
+		for j := 0; j < n; j++ {
+			sum += w[p-n+j] * in[i-n+1+j]
+			wsum += w[p-n+j]
+		}
+		out[i] = sum / wsum
+	}
+	return out
+}
+
+func refMcGinley(in []float64, p int) []float64 {
+	out := make([]float64, len(in))
+	md := in[0]
+	for i, v := range in {
+		if i > 0 {
+			md += (v - md) / (float64(p) * math.Pow(v/md, 4))
+		}
+		out[i] = md
+	}
+	return out
+}
+
+func refVIDYA(in []float64, p int) []float64 {
+	out := make([]float64, len(in))
+	vidya := in[0]
+	for i, v := range in {
+		if i > 0 {
+			var up, down float64
+			for j := i; j > 0 && j > i-p; j-- {
+				if d := in[j] - in[j-1]; d > 0 {
+					up += d
+				} else {
+					down -= d
+				}
+			}
+			var cmo float64
+			if up+down != 0 {
+				cmo = math.Abs(up-down) / (up + down)
+			}
+			k := 2 / float64(p+1) * cmo
+			vidya = k*v + (1-k)*vidya
+		}
+		out[i] = vidya
+	}
+	return out
+}
+
+func refFRAMA(in []float64, p int) []float64 {
+	p += p % 2
+	var (
+		out         = make([]float64, len(in))
+		half        = p / 2
+		dimen, filt float64
+	)
+	hiLo := func(vs []float64) (hi, lo float64) {
+		hi, lo = vs[0], vs[0]
+		for _, v := range vs {
+			hi, lo = math.Max(hi, v), math.Min(lo, v)
+		}
+		return hi, lo
+	}
+	for i, v := range in {
+		if i < p-1 {
+			filt, out[i] = v, v
+			continue
+		}
+		w := in[i-p+1 : i+1]
+		hi1, lo1 := hiLo(w[half:])
+		hi2, lo2 := hiLo(w[:half])
+		hi3, lo3 := hiLo(w)
+		n1, n2, n3 := (hi1-lo1)/float64(half), (hi2-lo2)/float64(half), (hi3-lo3)/float64(p)
+		if n1 > 0 && n2 > 0 && n3 > 0 {
+			dimen = (math.Log(n1+n2) - math.Log(n3)) / math.Ln2
+		}
+		alpha := math.Min(math.Max(math.Exp(-4.6*(dimen-1)), 0.01), 1)
+		filt = alpha*v + (1-alpha)*filt
+		out[i] = filt
+	}
+	return out
+}
+
+func refJMA(in []float64, p int) []float64 {
+	var (
+		out             = make([]float64, len(in))
+		beta            = 0.45 * float64(p-1) / (0.45*float64(p-1) + 2)
+		a               = beta * beta
+		e0, e1, e2, jma = in[0], 0.0, 0.0, in[0]
+	)
+	for i, v := range in {
+		if i > 0 {
+			e0 = (1-a)*v + a*e0
+			e1 = (v-e0)*(1-beta) + beta*e1
+			e2 = (e0+1.5*e1-jma)*(1-a)*(1-a) + a*a*e2
+			jma += e2
+		}
+		out[i] = jma
+	}
+	return out
+}
+
+func TestAdaptiveMAUpdate(t *testing.T) {
+	fns := map[string]MovingAverageFunc{
+		"HMA": HMA, "ZLEMA": ZLEMA, "ALMA": ALMA, "McGinley": McGinley,
+		"VIDYA": VIDYA, "FRAMA": FRAMA, "JMA": JMA,
+	}
+	for name, fn := range fns {
+		for _, p := range studyPeriods {
+			var (
+				ma    = fn(p)
+				chunk = fn(p)
+				ln    = testClose.Len()
+				mid   Decimal
+			)
+			for i := 0; i < ln/2; i++ {
+				mid = ma.Update(testClose.Get(i))
+			}
+
+			// multiple values are the same as updating them one by one
+			if v := chunk.Update(testClose.Slice(0, ln/2).v...); v != mid {
+				t.Fatalf("%s(%d): expected %v, got %v", name, p, mid, v)
+			}
+		}
+
+		// they work with the studies that take a moving average
+		if v := ApplyMultiVarStudy(MACDExt(12, 26, 9, fn), testClose)[0].Last(); v.IsNaN() || v.IsInf() {
+			t.Fatalf("%s: MACDExt returned %v", name, v)
+		}
+		if v := ApplyMultiVarStudy(BollingerBands(20, 2, 2, fn), testClose)[1].Last(); v.IsNaN() || v.IsInf() {
+			t.Fatalf("%s: BollingerBands returned %v", name, v)
+		}
+		if v := ApplyStudy(RSIExt(fn(14)), testClose).Last(); v.IsNaN() || v.IsInf() {
+			t.Fatalf("%s: RSIExt returned %v", name, v)
+		}
+	}
+}
+
+func TestPivotLevels(t *testing.T) {
+	c := &Candle{Open: 102, High: 110, Low: 100, Close: 108}
+	for m, exp := range map[PivotMethod][]Decimal{
+		PivotClassic:   {106, 112, 116, 122, 102, 96, 92},
+		PivotFibonacci: {106, 109.82, 112.18, 116, 102.18, 99.82, 96},
+		PivotCamarilla: {106, 108 + 11./12, 108 + 11./6, 110.75, 108 - 11./12, 108 - 11./6, 105.25},
+		PivotWoodie:    {106.5, 113, 116.5, 123, 103, 96.5, 93},
+		PivotDeMark:    {107, 114, 0, 0, 104, 0, 0},
+	} {
+		for i, v := range PivotLevels(m, c) {
+			if v.IsNaN() {
+				v = 0
+			}
+			if !decimal.EqualApprox(v.Float(), exp[i].Float(), 1e-9) {
+				t.Fatalf("%d [%d]: expected %v, got %v", m, i, exp[i], v)
+			}
+		}
+	}
+}
+
+func TestPivotsSessions(t *testing.T) {
+	var (
+		s    = Pivots(PivotClassic, nil)
+		day  = time.Date(2020, 1, 1, 9, 30, 0, 0, time.UTC)
+		prev *Candle
+	)
+	for d := 0; d < 3; d++ {
+		session := &Candle{}
+		for i := 0; i < 10; i++ {
+			ts := day.AddDate(0, 0, d).Add(time.Duration(i) * time.Hour)
+			c := &Candle{Open: Decimal(100 + d + i), High: Decimal(102 + d + i*2), Low: Decimal(99 + d - i), Close: Decimal(101 + d + i)}
+			if i == 0 {
+				*session = *c
+			}
+			session.High = decimal.Max(session.High, c.High)
+			session.Low = decimal.Min(session.Low, c.Low)
+			session.Close = c.Close
+
+			exp := make([]Decimal, 7)
+			if prev != nil {
+				exp = PivotLevels(PivotClassic, prev)
+			}
+			if v := s.UpdateCandleAt(ts, c); !decimal.SliceEqual(v, exp) {
+				t.Fatalf("[%d:%d] expected %v, got %v", d, i, exp, v)
+			}
+		}
+		prev = session
+	}
+
+	// without timestamps, each candle is a session
+	daily, candles := Pivots(PivotWoodie, nil), testCandles()
+	for i, c := range candles {
+		exp := make([]Decimal, 7)
+		if i > 0 {
+			exp = PivotLevels(PivotWoodie, candles[i-1])
+		}
+		if v := daily.UpdateCandle(c); !decimal.SliceEqual(v, exp) {
+			t.Fatalf("[%d] expected %v, got %v", i, exp, v)
+		}
+	}
+}
+
+func TestZigZag(t *testing.T) {
+	zz := ZigZag(5)
+	for i, c := range []struct {
+		v, price, typ Decimal
+	}{
+		{100, 0, 0}, {103, 0, 0},
+		{106, 100, -1}, // 6% off the low
+		{110, 0, 0}, {106, 0, 0},
+		{104, 110, 1}, // 5.45% off the high
+		{100, 0, 0}, {99, 0, 0}, {103, 0, 0},
+		{104, 99, -1}, // 5.05% off the low
+		{112, 0, 0},
+	} {
+		if v := zz.UpdateAll(c.v); v[0] != c.price || v[1] != c.typ {
+			t.Fatalf("[%d] expected [%v %v], got %v", i, c.price, c.typ, v)
+		}
+	}
+	exp := []Swing{{0, 100, false}, {3, 110, true}, {7, 99, false}}
+	if sw := zz.Swings(); len(sw) != len(exp) || sw[0] != exp[0] || sw[1] != exp[1] || sw[2] != exp[2] {
+		t.Fatalf("expected %v, got %v", exp, sw)
+	}
+}
+
+func TestZigZagATR(t *testing.T) {
+	// the 10.5 low on bar 5 isn't enough with the ATR of that bar, the top is only confirmed on bar 9
+	// once the ATR shrinks, the swing low must still be bar 5 and not the higher low of bar 9
+	zz := ZigZagATR(3, 3)
+	for i, c := range []*Candle{
+		{High: 10, Low: 9, Close: 9.5}, {High: 11, Low: 10, Close: 10.5}, {High: 12, Low: 11, Close: 11.5},
+		{High: 13, Low: 12, Close: 12.5}, {High: 14, Low: 13, Close: 13.5}, {High: 14, Low: 10.5, Close: 13.5},
+		{High: 12, Low: 11, Close: 11.5}, {High: 11.5, Low: 11, Close: 11.2}, {High: 11.3, Low: 11, Close: 11.1},
+		{High: 11.2, Low: 10.9, Close: 11}, {High: 15, Low: 11, Close: 14.8}, {High: 17, Low: 14.5, Close: 16.8},
+	} {
+		v := zz.UpdateCandle(c)
+		switch i {
+		case 9:
+			if v[0] != 14 || v[1] != 1 {
+				t.Fatalf("[%d] expected [14 1], got %v", i, v)
+			}
+		case 11:
+			if v[0] != 10.5 || v[1] != -1 {
+				t.Fatalf("[%d] expected [10.5 -1], got %v", i, v)
+			}
+		}
+	}
+	exp := []Swing{{0, 9, false}, {4, 14, true}, {5, 10.5, false}}
+	if sw := zz.Swings(); len(sw) != len(exp) || sw[0] != exp[0] || sw[1] != exp[1] || sw[2] != exp[2] {
+		t.Fatalf("expected %v, got %v", exp, sw)
+	}
+}
+
+func TestZigZagNoRepaint(t *testing.T) {
+	for _, fn := range []func() ZigZagStudy{
+		func() ZigZagStudy { return ZigZag(2) },
+		func() ZigZagStudy { return ZigZagATR(14, 3) },
+	} {
+		var (
+			batch = ApplyZigZag(fn(), testColumns)
+			zz    = fn()
+			n     int
+		)
+		if len(batch) < 2 {
+			t.Fatalf("expected some swings, got %v", batch)
+		}
+		for i, c := range testCandles() {
+			v := zz.UpdateCandle(c)
+			sw := zz.Swings()
+			if v[1] != 0 {
+				n++
+				if last := sw[len(sw)-1]; last.Price != v[0] || last.High != (v[1] > 0) || last.Index >= i {
+					t.Fatalf("[%d] unexpected swing %v for %v", i, last, v)
+				}
+			}
+			if len(sw) != n {
+				t.Fatalf("[%d] expected %d swings, got %d", i, n, len(sw))
+			}
+			// confirmed swings never change
+			for j := range sw {
+				if sw[j] != batch[j] {
+					t.Fatalf("[%d] swing %d changed: %v %v", i, j, sw[j], batch[j])
+				}
+				if j > 0 && sw[j].High == sw[j-1].High {
+					t.Fatalf("[%d] swings %d and %d have the same type", i, j-1, j)
+				}
+			}
+		}
+	}
+}
+
+func TestADXUpdateAll(t *testing.T) {
+	var (
+		adx  = ADX(14)
+		adxr = ADXR(14)
+		pdi  = PlusDI(14)
+		mdi  = MinusDI(14)
+	)
+	for i, c := range testCandles() {
+		all := adx.UpdateCandle(c)
+		if v := adxr.UpdateCandle(c); v[0] != all[0] || v[1] != all[1] || v[2] != all[2] {
+			t.Fatalf("[%d] ADXR and ADX mismatch: %v %v", i, v, all)
+		}
+		if v := pdi.Update(c.High, c.Low, c.Close); v != all[0] {
+			t.Fatalf("[%d] +DI mismatch: %v %v", i, v, all[0])
+		}
+		if v := mdi.Update(c.High, c.Low, c.Close); v != all[1] {
+			t.Fatalf("[%d] -DI mismatch: %v %v", i, v, all[1])
+		}
+	}
+}
+
+func TestDonchianChannels(t *testing.T) {
+	for _, p := range &[...]int{2, 5, 10, 20} {
+		dc := ApplyCandles(DonchianChannels(p), testColumns)
+		compare(t, dc[0], "result = talib.MAX(testHigh, %d)", p)
+		compare(t, dc[2], "result = talib.MIN(testLow, %d)", p)
+	}
+}
+
+func TestKeltnerChannels(t *testing.T) {
+	kc := ApplyCandles(KeltnerChannels(20, 10, 2, nil), testColumns)
+	compare(t, kc[1], "result = talib.EMA(testClose, 20)")
+	compare(t, kc[0].Sub(kc[1]), "result = talib.ATR(%s, 10) * 2", testHLC)
+	compare(t, kc[1].Sub(kc[2]), "result = talib.ATR(%s, 10) * 2", testHLC)
+}
+
+func TestChandelierExit(t *testing.T) {
+	ce := ApplyCandles(ChandelierExit(22, 3, nil), testColumns)
+	compare(t, ce[0], "result = talib.MAX(testHigh, 22) - talib.ATR(%s, 22) * 3", testHLC)
+	compare(t, ce[1], "result = talib.MIN(testLow, 22) + talib.ATR(%s, 22) * 3", testHLC)
+}
+
+func TestSuperTrend(t *testing.T) {
+	st := SuperTrend(10, 3, nil)
+	var prev []Decimal
+	for i, c := range testCandles() {
+		cur := st.UpdateCandle(c)
+		if cur[1] != 1 && cur[1] != -1 {
+			t.Fatalf("[%d] unexpected direction %v", i, cur[1])
+		}
+		if prev != nil && prev[1] != cur[1] {
+			if cur[1] > 0 && c.Close <= prev[0] {
+				t.Fatalf("[%d] flipped up with close %v <= %v", i, c.Close, prev[0])
+			}
+			if cur[1] < 0 && c.Close >= prev[0] {
+				t.Fatalf("[%d] flipped down with close %v >= %v", i, c.Close, prev[0])
+			}
+		}
+		prev = cur
+	}
+}
+
+func TestMultiTimeframe(t *testing.T) {
+	const n = 5
+	var (
+		candles = testCandles()
+		closed  = MultiTimeframe(func() Study { return RSI(14) }, n, false)
+		provRSI = MultiTimeframe(func() Study { return RSI(3) }, n, true)
+		provEMA = MultiTimeframe(func() Study { return EMA(4) }, n, true)
+		atr     = MultiTimeframe(func() Study { return ATR(3) }, n, false)
+
+		rsi, refATR      = RSI(14), ATR(3)
+		hist             []Decimal
+		lastRSI, lastATR Decimal
+		hb               bar
+	)
+
+	// replay returns the value of a new study updated with all the values
+	replay := func(s Study, vs []Decimal) (v Decimal) {
+		for _, x := range vs {
+			v = s.Update(x)
+		}
+		return v
+	}
+
+	for i, c := range candles {
+		b := barFromCandle(c)
+		if i%n == 0 {
+			hb = b
+		} else {
+			hb.merge(b)
+		}
+		done := i%n == n-1
+		if done {
+			lastRSI = rsi.Update(hb.close)
+			lastATR = refATR.Update(hb.high, hb.low, hb.close)
+			hist = append(hist, hb.close)
+		}
+
+		// no lookahead, the value only changes when a higher bar closes
+		if v := closed.UpdateCandle(c)[0]; v != lastRSI {
+			t.Fatalf("[%d] expected %v, got %v", i, lastRSI, v)
+		}
+		if v := atr.UpdateCandle(c)[0]; v != lastATR {
+			t.Fatalf("[%d] expected atr %v, got %v", i, lastATR, v)
+		}
+
+		// the provisional value is the value of the study if the forming bar closed now
+		seq := hist
+		if !done {
+			seq = append(hist[:len(hist):len(hist)], hb.close)
+		}
+		if v, exp := provRSI.Update(c.Close), replay(RSI(3), seq); v != exp {
+			t.Fatalf("[%d] expected provisional rsi %v, got %v", i, exp, v)
+		}
+		if v, exp := provEMA.Update(c.Close), replay(EMA(4), seq); v != exp {
+			t.Fatalf("[%d] expected provisional ema %v, got %v", i, exp, v)
+		}
+	}
+	if fc := closed.Forming(); fc != nil && len(candles)%n == 0 {
+		t.Fatalf("unexpected forming bar %v", fc)
+	}
+}
+
+func TestMultiTimeframeAt(t *testing.T) {
+	var (
+		t0      = time.Date(2021, 3, 1, 9, 30, 0, 0, time.UTC)
+		candles = testCandles()
+		ts      = func(i int) time.Time { return t0.Add(time.Duration(i) * time.Minute) }
+		bb      = NewBarBuilder(5*time.Minute, nil, false)
+		ref     = EMA(5)
+		mtf     = MultiTimeframeAt(func() Study { return EMA(5) }, 5*time.Minute, nil, false)
+		prov    = MultiTimeframeAt(func() Study { return EMA(5) }, 5*time.Minute, nil, true)
+		last    Decimal
+	)
+	for i, c := range candles {
+		// the bar closes on the first update after it ends
+		for _, b := range bb.UpdateCandle(ts(i), c) {
+			last = ref.Update(b.Close)
+		}
+		if v := mtf.UpdateCandleAt(ts(i), c)[0]; v != last {
+			t.Fatalf("[%d] expected %v, got %v", i, last, v)
+		}
+		if f := mtf.Forming(); f == nil || *f != bb.Current().Candle {
+			t.Fatalf("[%d] expected forming bar %v, got %v", i, bb.Current().Candle, f)
+		}
+		if v, exp := prov.UpdateCandleAt(ts(i), c)[0], ref.Clone().Update(bb.Current().Close); v != exp {
+			t.Fatalf("[%d] expected provisional %v, got %v", i, exp, v)
+		}
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected a panic")
+		}
+	}()
+	mtf.Update(1)
+}
+
+func TestRevisable(t *testing.T) {
+	candles := testCandles()
+	for name, fn := range map[string]func() Study{
+		"RSI":      func() Study { return RSI(14) },
+		"RSIExt":   func() Study { return RSIExt(WMA(10)) },
+		"CMO":      func() Study { return CMO(14) },
+		"EMA":      func() Study { return EMA(10) },
+		"WilderMA": func() Study { return WilderMA(10) },
+		"SMA":      func() Study { return SMA(10) },
+		"WMA":      func() Study { return WMA(10) },
+		"MACD":     func() Study { return MACD(12, 26, 9) },
+		"MACDExt":  func() Study { return MACDExt(12, 26, 9, SMA) },
+		"BBands":   func() Study { return BBands(20) },
+		"BBandsMA": func() Study { return BollingerBands(20, 2, 2, EMA) },
+		"VWAP":     func() Study { return VWAPBands(20, -20) },
+		"Variance": func() Study { return Variance(10) },
+		"StdDev":   func() Study { return StdDev(10) },
+		"Mean":     func() Study { return Mean(10) },
+		"KAMA":     func() Study { return KAMA(10) },
+		"RSIKAMA":  func() Study { return RSIExt(KAMA(10)) },
+	} {
+		in := func(c *Candle, v Decimal) []Decimal {
+			if name == "VWAP" {
+				return []Decimal{Decimal(c.Volume), v}
+			}
+			return []Decimal{v}
+		}
+
+		rs, ref := Revisable(fn()), Revisable(fn())
+		for i, c := range candles {
+			// a revision returns the same values as an update with the revised value
+			if i == len(candles)/2 {
+				exp := Revisable(fn())
+				for _, pc := range candles[:i] {
+					exp.UpdateAll(in(pc, pc.Close)...)
+				}
+				if got, exp := rs.Revise(in(c, c.High)...), exp.UpdateAll(in(c, c.High)...); !decimal.SliceEqual(got, exp) {
+					t.Fatalf("%s [%d]: expected revision %v, got %v", name, i, exp, got)
+				}
+			}
+
+			for _, v := range []Decimal{c.Open, c.High, c.Low} {
+				rs.Revise(in(c, v)...)
+			}
+
+			// N revisions then a commit, or an update, match a single update
+			var got []Decimal
+			if i%2 == 0 {
+				got = rs.Revise(in(c, c.Close)...)
+				rs.Commit()
+			} else {
+				got = rs.UpdateAll(in(c, c.Close)...)
+			}
+			if exp := ref.UpdateAll(in(c, c.Close)...); !decimal.SliceEqual(got, exp) {
+				t.Fatalf("%s [%d]: expected %v, got %v", name, i, exp, got)
+			}
+		}
+	}
+}
@@ -84,8 +84,18 @@ func (s *variance) UpdateAll(vs ...Decimal) []Decimal {
 	return []Decimal{v, v.Sqrt(), m1}
 }
 
-func (s *variance) Len() int               { return s.mean.Len() }
-func (s *variance) LenAll() []int          { return []int{s.Len()} }
+func (s *variance) Len() int      { return s.mean.Len() }
+func (s *variance) LenAll() []int { return []int{s.Len()} }
+func (s *variance) Lookback() int { return s.mean.Cap() - 1 }
+func (s *variance) Clone() Study  { return clone(s) }
+func (s *variance) Reset()        { *s = *newVar(s.mean.Cap(), s.mode) }
+
 func (s *variance) ToStudy() (Study, bool) { return s, true }
 
-func (s *variance) ToMulti() (MultiVarStudy, bool) { return s, true }
+// ToMulti returns false for Mean, it doesn't track the variance
+func (s *variance) ToMulti() (MultiVarStudy, bool) {
+	if s.mode&runMean == runMean {
+		return nil, false
+	}
+	return s, true
+}
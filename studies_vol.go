@@ -0,0 +1,315 @@
+package ta
+
+import "go.oneofone.dev/ta/decimal"
+
+// OBV - On Balance Volume
+func OBV() CandleStudy {
+	return &obv{}
+}
+
+var _ CandleStudy = (*obv)(nil)
+
+type obv struct {
+	obv  Decimal
+	prev Decimal
+	set  bool
+}
+
+func (s *obv) Update(vs ...Decimal) Decimal      { return s.update(barFromValues(vs)) }
+func (s *obv) UpdateAll(vs ...Decimal) []Decimal { return []Decimal{s.Update(vs...)} }
+func (s *obv) UpdateCandle(c *Candle) []Decimal  { return []Decimal{s.update(barFromCandle(c))} }
+
+func (s *obv) update(b bar) Decimal {
+	switch {
+	case !s.set:
+		s.obv, s.set = b.volume, true
+	case b.close > s.prev:
+		s.obv += b.volume
+	case b.close < s.prev:
+		s.obv -= b.volume
+	}
+	s.prev = b.close
+	return s.obv
+}
+
+func (s *obv) Len() int      { return 0 }
+func (s *obv) LenAll() []int { return []int{0} }
+func (s *obv) Lookback() int { return 0 }
+func (s *obv) Clone() Study  { return clone(s) }
+func (s *obv) Reset()        { *s = obv{} }
+
+func (s *obv) ToStudy() (Study, bool)         { return s, true }
+func (s *obv) ToMulti() (MultiVarStudy, bool) { return s, true }
+
+// AD - Chaikin Accumulation/Distribution Line
+func AD() CandleStudy {
+	return &ad{}
+}
+
+var _ CandleStudy = (*ad)(nil)
+
+type ad struct {
+	ad Decimal
+}
+
+func (s *ad) Update(vs ...Decimal) Decimal      { return s.update(barFromValues(vs)) }
+func (s *ad) UpdateAll(vs ...Decimal) []Decimal { return []Decimal{s.Update(vs...)} }
+func (s *ad) UpdateCandle(c *Candle) []Decimal  { return []Decimal{s.update(barFromCandle(c))} }
+
+func (s *ad) update(b bar) Decimal {
+	if hl := b.high - b.low; hl > 0 {
+		s.ad += (((b.close - b.low) - (b.high - b.close)) / hl) * b.volume
+	}
+	return s.ad
+}
+
+func (s *ad) Len() int      { return 0 }
+func (s *ad) LenAll() []int { return []int{0} }
+func (s *ad) Lookback() int { return 0 }
+func (s *ad) Clone() Study  { return clone(s) }
+func (s *ad) Reset()        { *s = ad{} }
+
+func (s *ad) ToStudy() (Study, bool)         { return s, true }
+func (s *ad) ToMulti() (MultiVarStudy, bool) { return s, true }
+
+// ADOSC - Chaikin A/D Oscillator, EMA(fastPeriod) - EMA(slowPeriod) of the A/D line
+// same as TA-Lib, the EMAs are seeded with the first A/D value rather than an SMA.
+func ADOSC(fastPeriod, slowPeriod int) CandleStudy {
+	checkPeriod(fastPeriod, 2)
+	checkPeriod(slowPeriod, 2)
+	return &adosc{
+		fastK:  2 / Decimal(fastPeriod+1),
+		slowK:  2 / Decimal(slowPeriod+1),
+		period: decimal.Max(fastPeriod, slowPeriod),
+	}
+}
+
+var _ CandleStudy = (*adosc)(nil)
+
+type adosc struct {
+	ad     ad
+	fast   Decimal
+	slow   Decimal
+	fastK  Decimal
+	slowK  Decimal
+	period int
+	set    bool
+}
+
+func (s *adosc) Update(vs ...Decimal) Decimal      { return s.update(barFromValues(vs)) }
+func (s *adosc) UpdateAll(vs ...Decimal) []Decimal { return []Decimal{s.Update(vs...)} }
+func (s *adosc) UpdateCandle(c *Candle) []Decimal  { return []Decimal{s.update(barFromCandle(c))} }
+
+func (s *adosc) update(b bar) Decimal {
+	ad := s.ad.update(b)
+	if !s.set {
+		s.fast, s.slow, s.set = ad, ad, true
+	} else {
+		s.fast = s.fastK*ad + (1-s.fastK)*s.fast
+		s.slow = s.slowK*ad + (1-s.slowK)*s.slow
+	}
+	return s.fast - s.slow
+}
+
+func (s *adosc) Len() int      { return s.period }
+func (s *adosc) LenAll() []int { return []int{s.period} }
+func (s *adosc) Lookback() int { return s.period - 1 }
+
+func (s *adosc) Clone() Study { return clone(s) }
+
+func (s *adosc) Reset() {
+	s.ad, s.fast, s.slow, s.set = ad{}, 0, 0, false
+}
+
+func (s *adosc) ToStudy() (Study, bool)         { return s, true }
+func (s *adosc) ToMulti() (MultiVarStudy, bool) { return s, true }
+
+// MFI - Money Flow Index, uses the typical price (hlc3)
+func MFI(period int) CandleStudy {
+	checkPeriod(period, 2)
+	return &mfi{
+		pos:    NewCapped(period),
+		neg:    NewCapped(period),
+		period: period,
+	}
+}
+
+var _ CandleStudy = (*mfi)(nil)
+
+type mfi struct {
+	pos    *TA
+	neg    *TA
+	sumPos Decimal
+	sumNeg Decimal
+	prevTP Decimal
+	period int
+	set    bool
+}
+
+func (s *mfi) Update(vs ...Decimal) Decimal      { return s.update(barFromValues(vs)) }
+func (s *mfi) UpdateAll(vs ...Decimal) []Decimal { return []Decimal{s.Update(vs...)} }
+func (s *mfi) UpdateCandle(c *Candle) []Decimal  { return []Decimal{s.update(barFromCandle(c))} }
+
+func (s *mfi) update(b bar) Decimal {
+	tp := (b.high + b.low + b.close) / 3
+	// the first bar doesn't have a previous typical price, so it doesn't count
+	if !s.set {
+		s.prevTP, s.set = tp, true
+		return 0
+	}
+
+	var pos, neg Decimal
+	if mf := tp * b.volume; tp > s.prevTP {
+		pos = mf
+	} else if tp < s.prevTP {
+		neg = mf
+	}
+	s.prevTP = tp
+
+	s.sumPos += pos - s.pos.Update(pos)
+	s.sumNeg += neg - s.neg.Update(neg)
+
+	// same as TA-Lib
+	if sum := s.sumPos + s.sumNeg; sum >= 1 {
+		return 100 * (s.sumPos / sum)
+	}
+	return 0
+}
+
+func (s *mfi) Len() int      { return s.period }
+func (s *mfi) LenAll() []int { return []int{s.period} }
+func (s *mfi) Lookback() int { return s.period }
+func (s *mfi) Clone() Study  { return clone(s) }
+func (s *mfi) Reset()        { *s = *MFI(s.period).(*mfi) }
+
+func (s *mfi) ToStudy() (Study, bool)         { return s, true }
+func (s *mfi) ToMulti() (MultiVarStudy, bool) { return s, true }
+
+// ForceIndex - Elder's Force Index, EMA(period) of (close - prev close) * volume,
+// a period < 2 returns the raw force index.
+func ForceIndex(period int) CandleStudy {
+	return &force{
+		ma:     optionalMA(EMA, period),
+		period: period,
+	}
+}
+
+var _ CandleStudy = (*force)(nil)
+
+type force struct {
+	ma     MovingAverage
+	prev   Decimal
+	period int
+	set    bool
+}
+
+func (s *force) Update(vs ...Decimal) Decimal      { return s.update(barFromValues(vs)) }
+func (s *force) UpdateAll(vs ...Decimal) []Decimal { return []Decimal{s.Update(vs...)} }
+func (s *force) UpdateCandle(c *Candle) []Decimal  { return []Decimal{s.update(barFromCandle(c))} }
+
+func (s *force) update(b bar) Decimal {
+	// the first bar doesn't have a previous close, so it doesn't count
+	if !s.set {
+		s.prev, s.set = b.close, true
+		return 0
+	}
+
+	fi := (b.close - s.prev) * b.volume
+	s.prev = b.close
+	if s.ma == nil {
+		return fi
+	}
+	return s.ma.Update(fi)
+}
+
+func (s *force) Len() int {
+	if s.ma == nil {
+		return 0
+	}
+	return s.period
+}
+
+func (s *force) Lookback() int { return 1 + Lookback(s.ma) }
+
+func (s *force) Clone() Study { return clone(s) }
+
+func (s *force) Reset() {
+	if s.ma != nil {
+		s.ma.Reset()
+	}
+	s.prev, s.set = 0, false
+}
+
+func (s *force) LenAll() []int { return []int{s.Len()} }
+
+func (s *force) ToStudy() (Study, bool)         { return s, true }
+func (s *force) ToMulti() (MultiVarStudy, bool) { return s, true }
+
+// EaseOfMovement - Ease of Movement, SMA(period) of scale * (hl2 - prev hl2) * (high - low) / volume,
+// if scale is 0, 10000 is used, a period < 2 returns the raw value.
+func EaseOfMovement(period int, scale Decimal) CandleStudy {
+	if scale == 0 {
+		scale = 10000
+	}
+	return &emv{
+		ma:     optionalMA(SMA, period),
+		scale:  scale,
+		period: period,
+	}
+}
+
+var _ CandleStudy = (*emv)(nil)
+
+type emv struct {
+	ma     MovingAverage
+	prev   Decimal
+	scale  Decimal
+	period int
+	set    bool
+}
+
+func (s *emv) Update(vs ...Decimal) Decimal      { return s.update(barFromValues(vs)) }
+func (s *emv) UpdateAll(vs ...Decimal) []Decimal { return []Decimal{s.Update(vs...)} }
+func (s *emv) UpdateCandle(c *Candle) []Decimal  { return []Decimal{s.update(barFromCandle(c))} }
+
+func (s *emv) update(b bar) Decimal {
+	mid := b.hl2()
+	// the first bar doesn't have a previous hl2, so it doesn't count
+	if !s.set {
+		s.prev, s.set = mid, true
+		return 0
+	}
+
+	var v Decimal
+	if b.volume != 0 {
+		v = s.scale * (mid - s.prev) * (b.high - b.low) / b.volume
+	}
+	s.prev = mid
+	if s.ma == nil {
+		return v
+	}
+	return s.ma.Update(v)
+}
+
+func (s *emv) Len() int {
+	if s.ma == nil {
+		return 0
+	}
+	return s.period
+}
+
+func (s *emv) Lookback() int { return 1 + Lookback(s.ma) }
+
+func (s *emv) Clone() Study { return clone(s) }
+
+func (s *emv) Reset() {
+	if s.ma != nil {
+		s.ma.Reset()
+	}
+	s.prev, s.set = 0, false
+}
+
+func (s *emv) LenAll() []int { return []int{s.Len()} }
+
+func (s *emv) ToStudy() (Study, bool)         { return s, true }
+func (s *emv) ToMulti() (MultiVarStudy, bool) { return s, true }
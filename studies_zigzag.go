@@ -0,0 +1,201 @@
+package ta
+
+import "math"
+
+// Swing is a confirmed swing high or low
+type Swing struct {
+	Index int     // the index of the bar, starting at 0 for the first update
+	Price Decimal // the high of a swing high or the low of a swing low
+	High  bool
+}
+
+// ZigZagStudy is returned by ZigZag and ZigZagATR
+type ZigZagStudy interface {
+	CandleStudy
+
+	// Swings returns all the confirmed swings, a swing never changes once it's confirmed
+	Swings() []Swing
+}
+
+// ZigZag returns a ZigZag study that confirms a swing once the price reverses pct percent from the swing's extreme,
+// it uses the high and low of every candle.
+// Update returns the price of the swing confirmed on the current bar, 0 if none
+// UpdateAll returns [price, type], type is 1 for a swing high, -1 for a swing low and 0 if no swing was confirmed
+func ZigZag(pct Decimal) ZigZagStudy {
+	if pct <= 0 {
+		panic("zigzag: pct must be > 0")
+	}
+	return &zigzag{pct: pct / 100}
+}
+
+// ZigZagATR same as ZigZag, except the reversal threshold is mult * ATR(period)
+func ZigZagATR(period int, mult Decimal) ZigZagStudy {
+	if mult <= 0 {
+		panic("zigzag: mult must be > 0")
+	}
+	return &zigzag{atr: ATR(period), mult: mult, period: period}
+}
+
+// ApplyZigZag applies the study to the candles and returns the confirmed swings,
+// use &Candles{Close: ta} for a single series.
+func ApplyZigZag(s ZigZagStudy, cs *Candles) []Swing {
+	for i := 0; i < cs.Len(); i++ {
+		b := cs.bar(i)
+		s.UpdateAll(b.open, b.high, b.low, b.close, b.volume)
+	}
+	return s.Swings()
+}
+
+var _ ZigZagStudy = (*zigzag)(nil)
+
+type zigzag struct {
+	atr    Study
+	swings []Swing
+
+	// the bars after the oldest extreme, first is the index of hist[0]
+	hist  []bar
+	first int
+
+	// the extremes since the last swing, only one is used once the direction is known,
+	// rlo is the lowest low after hi and rhi is the highest high after lo, they're only set once Index > the extreme's
+	hi, lo    Swing
+	rhi, rlo  Swing
+	pct, mult Decimal
+	idx, dir  int
+	period    int
+}
+
+func (s *zigzag) Update(vs ...Decimal) Decimal      { return s.UpdateAll(vs...)[0] }
+func (s *zigzag) UpdateAll(vs ...Decimal) []Decimal { return s.update(barFromValues(vs)) }
+func (s *zigzag) UpdateCandle(c *Candle) []Decimal  { return s.update(barFromCandle(c)) }
+
+func (s *zigzag) update(b bar) []Decimal {
+	idx := s.idx
+	s.idx++
+
+	var atr Decimal
+	if s.atr != nil {
+		atr = s.atr.Update(b.high, b.low, b.close)
+	}
+
+	if idx == 0 {
+		s.hi = Swing{Index: idx, Price: b.high, High: true}
+		s.lo = Swing{Index: idx, Price: b.low}
+		s.rhi, s.rlo = s.hi, s.lo
+		s.first = 1
+		return []Decimal{0, 0}
+	}
+	s.hist = append(s.hist, b)
+
+	// the bar that made the extreme can't confirm it, we don't know which came first
+	if s.dir >= 0 {
+		switch {
+		case b.high > s.hi.Price:
+			s.hi = Swing{Index: idx, Price: b.high, High: true}
+			s.rlo = Swing{Index: idx, Price: b.low}
+		case s.rlo.Index == s.hi.Index || b.low < s.rlo.Price:
+			s.rlo = Swing{Index: idx, Price: b.low}
+		}
+	}
+	if s.dir <= 0 {
+		switch {
+		case b.low < s.lo.Price:
+			s.lo = Swing{Index: idx, Price: b.low}
+			s.rhi = Swing{Index: idx, Price: b.high, High: true}
+		case s.rhi.Index == s.lo.Index || b.high > s.rhi.Price:
+			s.rhi = Swing{Index: idx, Price: b.high, High: true}
+		}
+	}
+
+	// the reversal is measured from the running extreme, the threshold can shrink after it was made
+	var (
+		top    = s.dir >= 0 && s.rlo.Index > s.hi.Index && s.hi.Price-s.rlo.Price >= s.threshold(idx, s.hi.Price, atr)
+		bottom = s.dir <= 0 && s.rhi.Index > s.lo.Index && s.rhi.Price-s.lo.Price >= s.threshold(idx, s.lo.Price, atr)
+	)
+	// before the first swing, the older extreme wins if both reversed on the same bar
+	if top && bottom {
+		top = s.hi.Index < s.lo.Index
+		bottom = !top
+	}
+
+	var out []Decimal
+	switch {
+	case top:
+		sw := s.hi
+		s.swings = append(s.swings, sw)
+		s.dir = -1
+		s.lo = s.rlo
+		s.rhi = s.retrace(s.lo, true)
+		out = []Decimal{sw.Price, 1}
+	case bottom:
+		sw := s.lo
+		s.swings = append(s.swings, sw)
+		s.dir = 1
+		s.hi = s.rhi
+		s.rlo = s.retrace(s.hi, false)
+		out = []Decimal{sw.Price, -1}
+	default:
+		out = []Decimal{0, 0}
+	}
+
+	// only the bars after the extremes that are still tracked are needed
+	keep := s.hi.Index
+	if s.dir < 0 || s.dir == 0 && s.lo.Index < keep {
+		keep = s.lo.Index
+	}
+	s.hist = s.hist[keep+1-s.first:]
+	s.first = keep + 1
+	return out
+}
+
+// retrace returns the highest high (or the lowest low) after the extreme sw
+func (s *zigzag) retrace(sw Swing, high bool) Swing {
+	r := Swing{Index: sw.Index, Price: sw.Price, High: high}
+	for i, b := range s.hist[sw.Index+1-s.first:] {
+		v := b.low
+		if high {
+			v = b.high
+		}
+		if i == 0 || high && v > r.Price || !high && v < r.Price {
+			r.Index, r.Price = sw.Index+1+i, v
+		}
+	}
+	return r
+}
+
+func (s *zigzag) threshold(idx int, price, atr Decimal) Decimal {
+	if s.atr != nil {
+		// don't confirm anything until the ATR has a full period
+		if idx < s.period {
+			return Decimal(math.Inf(1))
+		}
+		return atr * s.mult
+	}
+	return price.Abs() * s.pct
+}
+
+func (s *zigzag) Swings() []Swing {
+	return append([]Swing(nil), s.swings...)
+}
+
+func (s *zigzag) Len() int      { return 0 }
+func (s *zigzag) LenAll() []int { return []int{0, 0} }
+
+func (s *zigzag) Lookback() int {
+	if s.atr != nil {
+		return Lookback(s.atr)
+	}
+	return 0
+}
+
+func (s *zigzag) Clone() Study { return clone(s) }
+
+func (s *zigzag) Reset() {
+	if s.atr != nil {
+		s.atr.Reset()
+	}
+	*s = zigzag{atr: s.atr, pct: s.pct, mult: s.mult, period: s.period}
+}
+
+func (s *zigzag) ToStudy() (Study, bool)         { return s, true }
+func (s *zigzag) ToMulti() (MultiVarStudy, bool) { return s, true }
@@ -37,6 +37,15 @@ func NewCapped(size int) *TA {
 	}
 }
 
+// newRing returns a capped TA that starts empty and grows up to size,
+// unlike NewCapped, it won't include the zero values in Min/Max/Sum
+func newRing(size int) *TA {
+	return &TA{
+		v:   make([]Decimal, 0, size),
+		idx: new(int),
+	}
+}
+
 func NewSize(size int, cap bool) *TA {
 	if cap {
 		return &TA{v: make([]Decimal, 0, size)}
@@ -242,8 +251,33 @@ func (ta *TA) Raw() []Decimal {
 	return ta.v
 }
 
+// Copy returns a copy of ta, a copy of a capped TA has its own ring position
 func (ta *TA) Copy() *TA {
-	return &TA{v: append([]Decimal(nil), ta.v...), idx: ta.idx}
+	return ta.copyTo(nil)
+}
+
+// copyTo copies the values and ring position of ta to dst, reusing its buffer if possible,
+// if dst is nil a new TA is returned.
+func (ta *TA) copyTo(dst *TA) *TA {
+	if dst == nil {
+		dst = &TA{}
+	}
+	if cap(dst.v) != cap(ta.v) {
+		dst.v = make([]Decimal, len(ta.v), cap(ta.v))
+	}
+	dst.v = dst.v[:len(ta.v)]
+	copy(dst.v, ta.v)
+
+	switch {
+	case ta.idx == nil:
+		dst.idx = nil
+	case dst.idx == nil:
+		dst.idx = new(int)
+		fallthrough
+	default:
+		*dst.idx = *ta.idx
+	}
+	return dst
 }
 
 func (ta *TA) Equal(o *TA) bool {
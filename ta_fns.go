@@ -7,7 +7,8 @@ func HLC3(high, low, close *TA) *TA {
 	return Average(high, low, close)
 }
 
-// Average - returns the average of the passed in ta's
+// Average - returns the average of the passed in ta's,
+// values past the end of a shorter ta are averaged over the ta's that have them
 func Average(tas ...*TA) *TA {
 	ln := tas[0].Len()
 	for i := 1; i < len(tas); i++ {
@@ -19,12 +20,14 @@ func Average(tas ...*TA) *TA {
 	out := NewSize(ln, true)
 	for i := 0; i < ln; i++ {
 		var v Decimal
+		var n int
 		for _, ta := range tas {
 			if i < ta.Len() {
 				v = v.Add(ta.Get(i))
+				n++
 			}
 		}
-		out.Append(v / Decimal(ln))
+		out.Append(v / Decimal(n))
 	}
 
 	return out
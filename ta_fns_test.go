@@ -183,3 +183,17 @@ func TestCrossOverUnder(t *testing.T) {
 		t.Error("Crossover: expected and not found")
 	}
 }
+
+func TestAverage(t *testing.T) {
+	// divided by the number of ta's, not the length
+	exp := []float64{2, 3, 4}
+	if got := HLC3(New([]float64{3, 4, 5}), New([]float64{1, 2, 3}), New([]float64{2, 3, 4})); !got.Equal(New(exp)) {
+		t.Fatalf("expected %v, got %v", exp, got.Floats())
+	}
+
+	// the shorter ta only counts where it has values
+	exp = []float64{2, 4, 6}
+	if got := Average(New([]float64{1, 2, 6}), New([]float64{3, 6})); !got.Equal(New(exp)) {
+		t.Fatalf("expected %v, got %v", exp, got.Floats())
+	}
+}
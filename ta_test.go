@@ -119,6 +119,16 @@ func testMACD(t *testing.T, fast, slow, sig int, fn MovingAverageFunc, typ strin
 	})
 }
 
+func testMAMA(t *testing.T, fastLimit, slowLimit Decimal) {
+	t.Run(fmt.Sprintf("%v:%v", fastLimit, slowLimit), func(t *testing.T) {
+		out := ApplyMultiVarStudy(MAMAExt(200, fastLimit, slowLimit), testClose)
+		mama, fama := out[0], out[1]
+		pyfn := fmt.Sprintf("talib.MAMA(testClose, %f, %f)", fastLimit, slowLimit)
+		compare(t, mama, "result,_ = %s", pyfn)
+		compare(t, fama, "_,result = %s", pyfn)
+	})
+}
+
 func testBBands(t *testing.T, wg *sync.WaitGroup, period int, devUp, devDown Decimal, fn MovingAverageFunc, typ string) {
 	wg.Add(1)
 	go t.Run(fmt.Sprintf("%s:%v:%v:%v", typ, period, devUp, devDown), func(t *testing.T) {
@@ -148,6 +158,20 @@ func testStudy(t *testing.T, name string, fn func(period int) Study, maxPeriod i
 	}
 }
 
+func testCandleStudy(t *testing.T, name, args string, fn func(period int) CandleStudy, maxPeriod int) {
+	t.Parallel()
+	for _, period := range studyPeriods {
+		if maxPeriod > -1 && period > maxPeriod {
+			t.Skipf("%s > %d overflows python", name, maxPeriod)
+		}
+		t.Run(strconv.Itoa(period), func(t *testing.T) {
+			// compare the main (Update) output
+			res := ApplyStudy(fn(period), testOpen, testHigh, testLow, testClose, testVolume)
+			compare(t, res, "result = talib.%s(%s, %d)", name, args, period)
+		})
+	}
+}
+
 func testMA(t *testing.T, name string, fn MovingAverageFunc, maxPeriod int) {
 	testStudy(t, name, wrapMA(fn), maxPeriod)
 }
@@ -169,6 +193,20 @@ func benchMA(b *testing.B, name string, period int, fn MovingAverageFunc) {
 	benchStudy(b, name, period, wrapMA(fn))
 }
 
+func testCandles() []*Candle {
+	out := make([]*Candle, 0, testClose.Len())
+	for i := 0; i < testClose.Len(); i++ {
+		out = append(out, &Candle{
+			Open:   testOpen.Get(i),
+			High:   testHigh.Get(i),
+			Low:    testLow.Get(i),
+			Close:  testClose.Get(i),
+			Volume: int(testVolume.Get(i)),
+		})
+	}
+	return out
+}
+
 func randSlice(size int, seed int64, min, max Decimal) *TA {
 	r := rand.New(rand.NewSource(seed))
 	out := NewSize(size, true)
@@ -192,6 +230,8 @@ var (
 	testVolume = New([]float64{121465900, 169632600, 209151400, 125346700, 147217800, 158567300, 144396100, 214553300, 192991100, 176613900, 211879600, 130991100, 122942700, 174356000, 117516800, 92009700, 134044600, 168514300, 173585400, 197729700, 163107000, 124212900, 134306700, 97953200, 125672000, 87219000, 96164200, 91087800, 97545900, 93670400, 76968200, 80652900, 91462500, 140896400, 74411100, 72472300, 73061700, 72697900, 108076000, 87491400, 110325800, 114497200, 76873000, 188128000, 89818900, 157121300, 110145700, 93993500, 162410900, 136099200, 94510400, 228808500, 117917300, 177715100, 71784500, 77805300, 159521700, 153067200, 118939000, 96180400, 126768700, 137303600, 86900900, 114368200, 81236300, 89351900, 85548900, 72722900, 74436600, 75099900, 99529300, 68934900, 191113200, 92189500, 72559800, 78264600, 102585900, 61327400, 79358100, 86863500, 125684900, 161304900, 103399700, 70927200, 113326200, 135060200, 88244900, 155877300, 75708100, 119727600, 94667900, 95934000, 76510100, 74549700, 72114600, 76857500, 64764600, 57433500, 124308600, 93214000, 74974600, 124919600, 93338800, 91531000, 87820900, 151882800, 121704700, 89063300, 105034700, 134551300, 73876400, 135382400, 124384200, 85308200, 126708600, 165867900, 130478700, 70696000, 68476800, 92307300, 97107400, 104174800, 202621300, 182925100, 135979900, 104373700, 117975400, 173820200, 164020100, 144113100, 129456900, 106069400, 81709600, 97914100, 106683300, 89030000, 70446800, 77965000, 88667900, 90509100, 117755000, 132361100, 123544800, 105791300, 91304400, 103266900, 113965700, 81820800, 85786800, 116030800, 117858000, 80270700, 126081400, 172123700, 89383300, 72786500, 79072600, 71692700, 172946000, 194327900, 346588500, 507244300, 369833100, 339257000, 274143900, 160414400, 163298800, 256000400, 160269300, 152087800, 207081000, 116025700, 149347700, 158611100, 119691200, 79452000, 113806200, 99581600, 276046600, 223657500, 105726200, 153890900, 92790600, 159378800, 155054800, 178515900, 159045600, 163452000, 131079000, 211003300, 126320800, 110274500, 124307300, 153055200, 107069200, 56395600, 88038700, 99106200, 134142200, 109692900, 76523900, 78448500, 102038000, 174911700, 144442300, 69033000, 77905800, 135906700, 90525500, 131076900, 86270800, 95246100, 96224500, 78408700, 110471500, 131008700, 75874600, 67846000, 121315200, 153577100, 117645200, 121123700, 121342500, 88220500, 94011500, 64931200, 98874400, 51980100, 37317800, 112822700, 97858400, 108441300, 166224200, 192913900, 102027100, 103372400, 162401500, 116128900, 211173300, 182385200, 154069600, 197017000, 173092500, 251393500, 99094300, 111026200, 110987200, 48542200, 65899900, 92640700, 63317700, 114877900})
 	testRand   = New([]float64{0.42422904963267427, 0.16755615298728432, 0.5946077386900349, 0.17611040890583352, 0.29152918200482136, 0.27807733751955355, 0.7177400699036796, 0.5036012923358724, 0.1629504791237938, 0.6483065114032258, 0.5703588423748475, 0.7161845737507714, 0.6942714038794598, 0.42176699339445745, 0.7884431075157385, 0.24584359985404292, 0.7480158197252457, 0.2651217282085182, 0.4437589032368914, 0.9845738324910773, 0.5590040804528499, 0.25521017265864154, 0.1372114571360159, 0.1218701299153161, 0.25511876291008395, 0.7483943425884052, 0.076845841747889, 0.5389677976892574, 0.9015900382854415, 0.13503746751073498, 0.17237105554803778, 0.022111455150970016, 0.4735780024560894, 0.694458845807901, 0.5530772348613145, 0.3444350790493579, 0.6468662907768967, 0.6359557337589957, 0.5650572127602662, 0.621587087190788, 0.5634446451263618, 0.6967583014608363, 0.3366771423506647, 0.8920892600559512, 0.00029418556385873984, 0.1664001753124047, 0.2032534540019577, 0.30597531513267284, 0.4581883332445693, 0.4877258346021447})
 
+	testColumns = &Candles{Open: testOpen, High: testHigh, Low: testLow, Close: testClose, Volume: testVolume}
+
 	testCrossunder1 = New([]float64{1, 2, 3, 4, 8, 6, 7})
 	testCrossunder2 = New([]float64{1, 1, 10, 9, 5, 3, 7})
 
@@ -13,6 +13,14 @@ func checkPeriod(period, min int) {
 	}
 }
 
+// AggPipe averages the values from in every aggPeriod of wall clock time.
+//
+// Deprecated: use BarPipe, it builds OHLCV bars using the time of each trade.
 func AggPipe(aggPeriod time.Duration, in <-chan Decimal) <-chan Decimal {
 	return decimal.AggPipe(aggPeriod, in)
 }
+
+// isZero mirrors TA-Lib's TA_IS_ZERO
+func isZero(v Decimal) bool {
+	return v > -1e-14 && v < 1e-14
+}